@@ -0,0 +1,70 @@
+// Package auth loads container registry credentials the same way `docker
+// build`/`podman build` do: from a config.json-style credential file
+// (resolving credHelpers/credsStore through docker-credential-* helper
+// binaries) and from the FROM lines of a Dockerfile, so an in-cluster build
+// can authenticate against every registry it touches without the user
+// wiring each one in by hand.
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Config is the subset of the docker/podman config.json schema needed to
+// resolve credentials for a registry, including those backed by a
+// credential helper rather than a static auth entry.
+type Config struct {
+	Auths       map[string]json.RawMessage `json:"auths"`
+	CredHelpers map[string]string          `json:"credHelpers"`
+	CredsStore  string                     `json:"credsStore"`
+}
+
+// Load reads and parses the config.json-style file at path.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Auths == nil {
+		cfg.Auths = map[string]json.RawMessage{}
+	}
+	return cfg, nil
+}
+
+// DefaultConfigPath returns the first credential file found by searching,
+// in order: $DOCKER_CONFIG/config.json, ~/.docker/config.json, Podman's
+// $REGISTRY_AUTH_FILE, $XDG_RUNTIME_DIR/containers/auth.json, and
+// ~/.config/containers/auth.json. It returns "" if none exist.
+func DefaultConfigPath() string {
+	var candidates []string
+
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "config.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".docker", "config.json"))
+	}
+	if path := os.Getenv("REGISTRY_AUTH_FILE"); path != "" {
+		candidates = append(candidates, path)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "containers", "auth.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "containers", "auth.json"))
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
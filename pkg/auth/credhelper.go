@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/openshift/odo/pkg/log"
+)
+
+// credHelperResponse is what `docker-credential-<helper> get` writes to
+// stdout for a successful lookup.
+type credHelperResponse struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// ResolveAuth returns the raw docker config.json auth entry for registry,
+// preferring a static entry in c.Auths, then c.CredHelpers[registry], then
+// c.CredsStore. It returns ok=false if none of those have credentials for
+// registry, or if the helper that would resolve them isn't installed: a
+// missing docker-credential-<helper> binary on $PATH is logged as a
+// warning and treated as "no credentials for this registry" rather than
+// failing the caller's entire credential resolution.
+func (c *Config) ResolveAuth(registry string) (json.RawMessage, bool, error) {
+	if entry, ok := c.Auths[registry]; ok {
+		return entry, true, nil
+	}
+
+	helper := c.CredHelpers[registry]
+	if helper == "" {
+		helper = c.CredsStore
+	}
+	if helper == "" {
+		return nil, false, nil
+	}
+
+	helperBinary := "docker-credential-" + helper
+	if _, err := exec.LookPath(helperBinary); err != nil {
+		log.Warningf("%s is not on PATH, skipping credentials for registry %s", helperBinary, registry)
+		return nil, false, nil
+	}
+
+	entry, err := entryFromCredHelper(helper, registry)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+// entryFromCredHelper shells out to docker-credential-<helper> get,
+// feeding it registry on stdin, and converts the response into a docker
+// config.json auth entry (a base64-encoded "user:pass").
+func entryFromCredHelper(helper, registry string) (json.RawMessage, error) {
+	cmd := exec.Command(fmt.Sprintf("docker-credential-%s", helper), "get")
+	cmd.Stdin = strings.NewReader(registry)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("docker-credential-%s get %s: %w: %s", helper, registry, err, stderr.String())
+	}
+
+	var resp credHelperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("docker-credential-%s returned an unexpected response for %s: %w", helper, registry, err)
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(resp.Username + ":" + resp.Secret))
+	entry, err := json.Marshal(map[string]string{"auth": auth})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
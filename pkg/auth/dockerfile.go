@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var fromLineRegexp = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--platform=\S+\s+)?(\S+)`)
+
+// RegistriesFromDockerfile returns the distinct registry hosts referenced
+// by the FROM lines of the Dockerfile at path, so a build can authenticate
+// against private base image registries it never explicitly configured.
+// Stage aliases ("FROM builder AS runtime") and the implicit docker.io
+// default are not treated as registries needing credentials.
+func RegistriesFromDockerfile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	seen := map[string]bool{}
+	var registries []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := fromLineRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		registry := RegistryFromImageRef(match[1])
+		if registry == "" || seen[registry] {
+			continue
+		}
+		seen[registry] = true
+		registries = append(registries, registry)
+	}
+
+	return registries, scanner.Err()
+}
+
+// RegistryFromImageRef returns the registry host portion of an image
+// reference (e.g. "quay.io/foo/bar:tag" -> "quay.io"), or "" for a bare
+// Docker Hub reference, a stage alias, or a scratch/build-arg reference
+// that isn't a real registry.
+func RegistryFromImageRef(ref string) string {
+	if ref == "" || ref == "scratch" || strings.HasPrefix(ref, "$") {
+		return ""
+	}
+
+	firstSegment := ref
+	if idx := strings.Index(ref, "/"); idx != -1 {
+		firstSegment = ref[:idx]
+	} else {
+		// A single-segment reference like "alpine:3.18" has no registry of
+		// its own; it resolves against the default Docker Hub registry.
+		return ""
+	}
+
+	if !strings.ContainsAny(firstSegment, ".:") && firstSegment != "localhost" {
+		// Something like "library/alpine" - still Docker Hub, not a registry host.
+		return ""
+	}
+
+	return firstSegment
+}
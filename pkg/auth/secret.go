@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// dockerConfigJSON is the `kubernetes.io/dockerconfigjson` Secret payload
+// shape: a single "auths" map keyed by registry host.
+type dockerConfigJSON struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// DockerConfigJSON builds a `.dockerconfigjson` payload covering pushTag's
+// registry and every registry referenced by a FROM line in the Dockerfile
+// at dockerfilePath, resolving each one's credentials (including
+// credHelpers/credsStore) from the config.json-style file at configPath.
+// Either pushTag or dockerfilePath may be empty; configPath may be empty if
+// no local credential file was found, in which case only registries that
+// need no authentication will resolve.
+func DockerConfigJSON(pushTag, dockerfilePath, configPath string) ([]byte, error) {
+	var registries []string
+	if host := RegistryFromImageRef(pushTag); host != "" {
+		registries = append(registries, host)
+	}
+	if dockerfilePath != "" {
+		fromRegistries, err := RegistriesFromDockerfile(dockerfilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		registries = append(registries, fromRegistries...)
+	}
+
+	merged := dockerConfigJSON{Auths: map[string]json.RawMessage{}}
+	if configPath != "" && len(registries) > 0 {
+		cfg, err := Load(configPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, registry := range registries {
+			entry, ok, err := cfg.ResolveAuth(registry)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				merged.Auths[registry] = entry
+			}
+		}
+	}
+
+	return json.Marshal(merged)
+}
@@ -0,0 +1,265 @@
+package build
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/odo/pkg/auth"
+	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/utils"
+	"github.com/openshift/odo/pkg/kclient"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const dockerConfigJSONKey = ".dockerconfigjson"
+
+var secretGroupVersionResource = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+// RegistryAuth configures how a Strategy authenticates to the destination
+// (and any upstream) container registries. It replaces odo's historical
+// assumption of a single "regcred" Secret.
+type RegistryAuth struct {
+	// SecretName, if set, is the name to give the Secret odo materializes
+	// from ConfigFile/ExtraConfigFiles. Defaults to "<component>-regcred".
+	SecretName string
+
+	// ConfigFile is a path to a local docker/podman config.json-style
+	// credential file (e.g. ~/.docker/config.json, or a path from
+	// KANIKO_SECRET_CONFIG_FILE) that odo reads and materializes as a
+	// Secret in the namespace before the build starts.
+	ConfigFile string
+
+	// ExtraConfigFiles lists additional config.json files whose `auths`
+	// entries are merged into the same Secret, so a build can authenticate
+	// against more than one registry (e.g. a private base image registry
+	// plus the push target).
+	ExtraConfigFiles []string
+
+	// ServiceAccount names an existing ServiceAccount whose image pull
+	// secrets are discovered and merged into the resulting Secret, instead
+	// of (or in addition to) ConfigFile. The same ServiceAccount is also
+	// assigned to the builder pod so that cloud workload-identity
+	// annotations on it still apply.
+	ServiceAccount string
+
+	// PushTag is the destination image reference this build will push to.
+	// Together with DockerfilePath it's used to auto-discover every
+	// registry the build touches (push target and FROM base images) in the
+	// local docker/podman credential store, the same way `docker build`
+	// would.
+	PushTag string
+
+	// DockerfilePath is the path to the project's Dockerfile, parsed for
+	// FROM lines referencing private base image registries.
+	DockerfilePath string
+
+	// Entries are explicit registry=path/to/config.json mappings, as
+	// produced by repeated `--registry-auth` flags: each one resolves
+	// credentials for exactly the named registry out of the named config
+	// file, rather than merging every registry that file happens to
+	// mention.
+	Entries []RegistryAuthEntry
+
+	// FromSecretName, if set, short-circuits all of the above: instead of
+	// materializing a new Secret from local files/ServiceAccount/auto-
+	// discovery, ResolveSecret copies the named existing in-cluster Secret
+	// (as produced by `--registry-auth-from-secret`).
+	FromSecretName string
+}
+
+// RegistryAuthEntry is a single `--registry-auth registry=path` mapping.
+type RegistryAuthEntry struct {
+	Registry   string
+	ConfigFile string
+}
+
+// ParseRegistryAuthFlags parses repeated `--registry-auth registry=path`
+// flag values into RegistryAuthEntry values.
+func ParseRegistryAuthFlags(flags []string) ([]RegistryAuthEntry, error) {
+	var entries []RegistryAuthEntry
+	for _, flag := range flags {
+		idx := strings.Index(flag, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid --registry-auth value %q, expected registry=path/to/config.json", flag)
+		}
+		entries = append(entries, RegistryAuthEntry{
+			Registry:   flag[:idx],
+			ConfigFile: flag[idx+1:],
+		})
+	}
+	return entries, nil
+}
+
+// dockerConfigJSON is the subset of the docker/podman config.json schema
+// odo needs in order to merge credentials from multiple sources.
+type dockerConfigJSON struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// ResolveSecret materializes auth as a single `kubernetes.io/dockerconfigjson`
+// Secret in namespace and returns its name, or "" if none of auth's
+// credential sources yields any registry credentials.
+func ResolveSecret(client *kclient.Client, namespace, componentName string, registryAuth RegistryAuth) (string, error) {
+	secretName := registryAuth.SecretName
+	if secretName == "" {
+		secretName = componentName + "-regcred"
+	}
+
+	if registryAuth.FromSecretName != "" {
+		return secretName, CopySecret(client, namespace, registryAuth.FromSecretName, secretName)
+	}
+
+	merged := dockerConfigJSON{Auths: map[string]json.RawMessage{}}
+
+	configFiles := registryAuth.ExtraConfigFiles
+	if registryAuth.ConfigFile != "" {
+		configFiles = append([]string{registryAuth.ConfigFile}, configFiles...)
+	}
+	for _, path := range configFiles {
+		data, err := utils.CreateDockerConfigDataFromFilepath(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read registry credentials from %s", path)
+		}
+		if err := mergeDockerConfig(&merged, data[dockerConfigJSONKey]); err != nil {
+			return "", errors.Wrapf(err, "unable to parse registry credentials from %s", path)
+		}
+	}
+
+	if registryAuth.ServiceAccount != "" {
+		secrets, err := pullSecretsForServiceAccount(client, namespace, registryAuth.ServiceAccount)
+		if err != nil {
+			return "", err
+		}
+		for _, data := range secrets {
+			if err := mergeDockerConfig(&merged, data); err != nil {
+				return "", errors.Wrapf(err, "unable to parse image pull secret for service account %s", registryAuth.ServiceAccount)
+			}
+		}
+	}
+
+	if registryAuth.PushTag != "" || registryAuth.DockerfilePath != "" {
+		localConfigPath := registryAuth.ConfigFile
+		if localConfigPath == "" {
+			localConfigPath = auth.DefaultConfigPath()
+		}
+		localAuths, err := auth.DockerConfigJSON(registryAuth.PushTag, registryAuth.DockerfilePath, localConfigPath)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to auto-discover local registry credentials")
+		}
+		if err := mergeDockerConfig(&merged, localAuths); err != nil {
+			return "", errors.Wrap(err, "unable to parse auto-discovered registry credentials")
+		}
+	}
+
+	for _, entry := range registryAuth.Entries {
+		cfg, err := auth.Load(entry.ConfigFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read --registry-auth config file %s for registry %s", entry.ConfigFile, entry.Registry)
+		}
+		resolved, ok, err := cfg.ResolveAuth(entry.Registry)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to resolve --registry-auth credentials for registry %s", entry.Registry)
+		}
+		if ok {
+			merged.Auths[entry.Registry] = resolved
+		}
+	}
+
+	if len(merged.Auths) == 0 {
+		return "", nil
+	}
+
+	mergedBytes, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+
+	secretUnstructured, err := utils.CreateSecret(secretName, namespace, map[string][]byte{dockerConfigJSONKey: mergedBytes})
+	if err != nil {
+		return "", err
+	}
+	// Recreated per build rather than long-lived, so odo has seen this name
+	// Create-fail on a second push before; CreateOrUpdate makes this
+	// idempotent by falling back to an Update on IsAlreadyExists.
+	if _, err := utils.CreateOrUpdate(client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace), secretUnstructured, nil, utils.DefaultRetryOptions); err != nil {
+		return "", errors.Wrapf(err, "unable to create registry credentials secret %s", secretName)
+	}
+
+	return secretName, nil
+}
+
+// CopySecret copies the `.dockerconfigjson` data of the existing Secret
+// named sourceName into a Secret named destName, materializing destName if
+// it doesn't already exist or updating it in place if it does. This backs
+// --registry-auth-from-secret, which reuses credentials a cluster admin has
+// already placed in the namespace instead of odo deriving a new Secret from
+// local files. Exported so other packages (e.g.
+// component.createDockerConfigSecret) that need the same copy behavior for
+// their own dockerconfigjson Secret don't have to reimplement it.
+func CopySecret(client *kclient.Client, namespace, sourceName, destName string) error {
+	source, err := client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace).Get(sourceName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get registry credentials secret %s", sourceName)
+	}
+
+	data, found, err := unstructured.NestedStringMap(source.Object, "data")
+	if err != nil {
+		return errors.Wrapf(err, "unable to read data of registry credentials secret %s", sourceName)
+	}
+	if !found || data[dockerConfigJSONKey] == "" {
+		return fmt.Errorf("secret %s has no %s entry", sourceName, dockerConfigJSONKey)
+	}
+	rawAuth, err := base64.StdEncoding.DecodeString(data[dockerConfigJSONKey])
+	if err != nil {
+		return errors.Wrapf(err, "secret %s has an invalid %s entry", sourceName, dockerConfigJSONKey)
+	}
+
+	destUnstructured, err := utils.CreateSecret(destName, namespace, map[string][]byte{dockerConfigJSONKey: rawAuth})
+	if err != nil {
+		return err
+	}
+	_, err = utils.CreateOrUpdate(client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace), destUnstructured, nil, utils.DefaultRetryOptions)
+	return errors.Wrapf(err, "unable to copy registry credentials secret %s to %s", sourceName, destName)
+}
+
+// pullSecretsForServiceAccount returns the raw `.dockerconfigjson` payload
+// of every image pull secret referenced by the named ServiceAccount.
+func pullSecretsForServiceAccount(client *kclient.Client, namespace, name string) ([][]byte, error) {
+	sa, err := client.KubeClient.CoreV1().ServiceAccounts(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get service account %s", name)
+	}
+
+	var configs [][]byte
+	for _, ref := range sa.ImagePullSecrets {
+		secret, err := client.KubeClient.CoreV1().Secrets(namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to get image pull secret %s referenced by service account %s", ref.Name, name)
+		}
+		if data, ok := secret.Data[dockerConfigJSONKey]; ok {
+			configs = append(configs, data)
+		}
+	}
+	return configs, nil
+}
+
+// mergeDockerConfig merges the `auths` entries of a raw config.json
+// payload into dst, with later merges overriding earlier ones for the same
+// registry host.
+func mergeDockerConfig(dst *dockerConfigJSON, raw []byte) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	var parsed dockerConfigJSON
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return fmt.Errorf("invalid docker config.json: %w", err)
+	}
+	for registry, auth := range parsed.Auths {
+		dst.Auths[registry] = auth
+	}
+	return nil
+}
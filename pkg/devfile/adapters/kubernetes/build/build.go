@@ -0,0 +1,114 @@
+// Package build provides pluggable strategies for building and pushing a
+// devfile component's container image from within the cluster. Each
+// strategy (Kaniko, BuildKit, Buildah, ...) owns its own builder pod spec,
+// volume layout, secret mounting and completion signalling, so adding a new
+// builder never requires touching the others.
+package build
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/openshift/odo/pkg/kclient"
+)
+
+// DefaultStrategy is used when the devfile/CLI do not request a specific
+// build strategy, preserving the historical odo behaviour.
+const DefaultStrategy = "kaniko"
+
+// Parameters bundles everything a Strategy needs to build and push a
+// component image. It is assembled by the kubernetes component adapter so
+// that individual strategies stay free of adapter-specific concerns like
+// syncing source to the cluster.
+type Parameters struct {
+	// Client is used to create the builder pod and any supporting
+	// resources (Secrets, PVCs) the strategy requires.
+	Client *kclient.Client
+
+	// ComponentName names and labels the resources the strategy creates.
+	ComponentName string
+
+	// BuildParameters carries the user-supplied build configuration, such
+	// as the destination image tag and whether a rootless build was
+	// requested.
+	BuildParameters common.BuildParameters
+
+	// SecretName is the name of an already-created registry credentials
+	// Secret to mount into the builder pod. Empty when pushing to an
+	// internal registry that does not require authentication. Resolve it
+	// with ResolveSecret.
+	SecretName string
+
+	// ServiceAccountName, when set, is assigned to the builder pod so that
+	// cloud-provider credential injection tied to a ServiceAccount (GCR
+	// workload identity, ECR IRSA, ACR managed identity) applies to it the
+	// same way it would to the running component.
+	ServiceAccountName string
+
+	// Sync streams the build context (the synced project source) into the
+	// named container of the builder pod once that container is ready to
+	// receive it. Strategies call Sync as part of their handoff sequence.
+	Sync func(podName, containerName string) error
+
+	// Quiet suppresses the "[build]"-prefixed streaming of the builder
+	// container's logs to stdout, e.g. when odo is run with -q. The last
+	// lines of output are still captured and surfaced on failure.
+	Quiet bool
+
+	// Cache configures build layer-cache reuse across `odo push`
+	// invocations. Strategies that don't support layer caching may ignore
+	// it.
+	Cache Cache
+}
+
+// Cache configures a Strategy's layer-cache reuse. The zero value disables
+// caching.
+type Cache struct {
+	// Enabled turns on the strategy's layer cache.
+	Enabled bool
+
+	// Repo, when set, is a remote registry location (e.g.
+	// "<registry>/<component>-cache") the strategy should push/pull cached
+	// layers to/from instead of a local PVC.
+	Repo string
+
+	// PVCSize is the requested size of the PVC provisioned to back the
+	// local cache when Repo is empty. Defaults to "2Gi".
+	PVCSize string
+}
+
+// Strategy builds and pushes a component's container image using a
+// particular builder implementation.
+type Strategy interface {
+	// Name identifies the strategy as used in the devfile's
+	// `alpha.build-strategy` attribute and the `--build-strategy` CLI flag.
+	Name() string
+
+	// Build creates the builder pod, hands off the build context to it via
+	// Parameters.Sync, and blocks until the build has completed or ctx is
+	// cancelled. A cancelled ctx must result in the builder pod being
+	// deleted before Build returns.
+	Build(ctx context.Context, params Parameters) error
+}
+
+var strategies = map[string]Strategy{}
+
+// Register makes a Strategy available under its Name(). Strategies call
+// this from their package init().
+func Register(s Strategy) {
+	strategies[s.Name()] = s
+}
+
+// Get returns the registered Strategy for name, defaulting to
+// DefaultStrategy when name is empty.
+func Get(name string) (Strategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+	s, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown build strategy %q, expected one of: kaniko, buildkit, buildah", name)
+	}
+	return s, nil
+}
@@ -0,0 +1,96 @@
+package build
+
+import (
+	"context"
+
+	"github.com/openshift/odo/pkg/kclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func init() {
+	Register(buildahStrategy{})
+}
+
+const (
+	buildahImage         = "quay.io/buildah/stable:latest"
+	buildahContainerName = "buildah"
+	buildahInitContainer = "init"
+	buildahSecret        = "buildah-secret"
+)
+
+// buildahStrategy builds images with Buildah running in a privileged pod
+// using chroot isolation, trading the rootless constraints of Kaniko and
+// BuildKit for full Dockerfile feature support (e.g. RUN --mount).
+type buildahStrategy struct{}
+
+func (buildahStrategy) Name() string { return "buildah" }
+
+func (b buildahStrategy) Build(ctx context.Context, params Parameters) error {
+	return runBuild(ctx, params, "Buildah", buildahInitContainer, buildahContainerName, b.createBuilderPod)
+}
+
+func (b buildahStrategy) createBuilderPod(params Parameters, labels map[string]string) error {
+	objectMeta := kclient.CreateObjectMeta(params.ComponentName, params.Client.Namespace, labels, nil)
+	volumes := []corev1.Volume{
+		{Name: buildContext, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: buildContext, MountPath: buildContextMountPath},
+	}
+
+	script := "buildah bud --isolation=chroot -t " + params.BuildParameters.Tag + " " + buildContextMountPath +
+		" && buildah push " + params.BuildParameters.Tag
+
+	if params.SecretName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: buildahSecret,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: params.SecretName,
+					Items:      []corev1.KeyToPath{{Key: ".dockerconfigjson", Path: "config.json"}},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: buildahSecret, MountPath: "/var/lib/containers/auth"})
+		script = "buildah login --authfile /var/lib/containers/auth/config.json && " + script
+	}
+
+	privileged := true
+	pod := &corev1.Pod{
+		ObjectMeta: objectMeta,
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: params.ServiceAccountName,
+			InitContainers: []corev1.Container{
+				{
+					Name:            buildahInitContainer,
+					Image:           "busybox",
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"/bin/sh", "-c"},
+					Args:            []string{initContainerHandoffScript},
+					VolumeMounts:    []corev1.VolumeMount{{Name: buildContext, MountPath: buildContextMountPath}},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            buildahContainerName,
+					Image:           buildahImage,
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"/bin/sh", "-c"},
+					Args:            []string{script},
+					VolumeMounts:    volumeMounts,
+					SecurityContext: &corev1.SecurityContext{
+						// buildah bud --isolation=chroot requires a
+						// privileged pod to create the mount namespaces
+						// for its build containers.
+						Privileged: &privileged,
+					},
+				},
+			},
+			Volumes: volumes,
+		},
+	}
+
+	_, err := params.Client.KubeClient.CoreV1().Pods(params.Client.Namespace).Create(pod)
+	return err
+}
@@ -0,0 +1,110 @@
+package build
+
+import (
+	"context"
+
+	"github.com/openshift/odo/pkg/kclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func init() {
+	Register(buildkitStrategy{})
+}
+
+const (
+	buildkitImage         = "moby/buildkit:rootless"
+	buildkitContainerName = "buildkitd"
+	buildkitInitContainer = "init"
+	buildkitSecret        = "buildkit-secret"
+)
+
+// buildkitUserId is the UID buildkitd's rootless mode runs as. Unlike
+// Kaniko (which needs the root defaultId), the buildkitd container sets
+// RunAsNonRoot: true, so the pod-level RunAsUser must be non-zero too -
+// Kubernetes refuses to start a container whose effective UID is 0 when
+// RunAsNonRoot is set.
+var buildkitUserId = int64(1000)
+
+// buildkitStrategy builds images with a rootless buildkitd/buildctl pair,
+// an alternative to Kaniko for clusters that allow the extra seccomp
+// profile buildkitd's rootless mode requires.
+type buildkitStrategy struct{}
+
+func (buildkitStrategy) Name() string { return "buildkit" }
+
+func (b buildkitStrategy) Build(ctx context.Context, params Parameters) error {
+	return runBuild(ctx, params, "BuildKit", buildkitInitContainer, buildkitContainerName, b.createBuilderPod)
+}
+
+func (b buildkitStrategy) createBuilderPod(params Parameters, labels map[string]string) error {
+	objectMeta := kclient.CreateObjectMeta(params.ComponentName, params.Client.Namespace, labels, nil)
+	volumes := []corev1.Volume{
+		{Name: buildContext, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: buildContext, MountPath: buildContextMountPath},
+	}
+
+	args := []string{
+		"build",
+		"--frontend=dockerfile.v0",
+		"--local", "context=" + buildContextMountPath,
+		"--local", "dockerfile=" + buildContextMountPath,
+		"--output", "type=image,name=" + params.BuildParameters.Tag + ",push=true",
+	}
+
+	if params.SecretName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: buildkitSecret,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: params.SecretName,
+					Items:      []corev1.KeyToPath{{Key: ".dockerconfigjson", Path: "config.json"}},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: buildkitSecret, MountPath: "/root/.docker"})
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: objectMeta,
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: params.ServiceAccountName,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser: &buildkitUserId,
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name:            buildkitInitContainer,
+					Image:           "busybox",
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"/bin/sh", "-c"},
+					Args:            []string{initContainerHandoffScript},
+					VolumeMounts:    []corev1.VolumeMount{{Name: buildContext, MountPath: buildContextMountPath}},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            buildkitContainerName,
+					Image:           buildkitImage,
+					ImagePullPolicy: corev1.PullAlways,
+					Args:            args,
+					VolumeMounts:    volumeMounts,
+					SecurityContext: &corev1.SecurityContext{
+						// buildkitd's rootless mode still requires the
+						// unprivileged user namespace seccomp profile, but
+						// never the host's root user.
+						RunAsNonRoot: boolPtr(true),
+					},
+				},
+			},
+			Volumes: volumes,
+		},
+	}
+
+	_, err := params.Client.KubeClient.CoreV1().Pods(params.Client.Namespace).Create(pod)
+	return err
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,54 @@
+package build
+
+import (
+	"github.com/openshift/odo/pkg/kclient"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultCachePVCSize = "2Gi"
+
+// cachePVCName returns the name of the PVC that backs componentName's
+// build cache. It is labeled with the component name so later `odo push`
+// invocations resolve and reattach the same PVC instead of starting the
+// cache cold.
+func cachePVCName(componentName string) string {
+	return componentName + "-build-cache"
+}
+
+// ensureCachePVC creates the PVC backing a component's build cache if it
+// does not already exist, and returns its name either way.
+func ensureCachePVC(client *kclient.Client, componentName string, cache Cache) (string, error) {
+	name := cachePVCName(componentName)
+	size := cache.PVCSize
+	if size == "" {
+		size = defaultCachePVCSize
+	}
+
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid build cache size %q", size)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: kclient.CreateObjectMeta(name, client.Namespace, map[string]string{
+			"component": componentName,
+		}, nil),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		},
+	}
+
+	_, err = client.KubeClient.CoreV1().PersistentVolumeClaims(client.Namespace).Create(pvc)
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return "", errors.Wrapf(err, "unable to create build cache PVC %s", name)
+	}
+
+	return name, nil
+}
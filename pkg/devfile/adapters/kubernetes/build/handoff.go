@@ -0,0 +1,28 @@
+package build
+
+import (
+	"bytes"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/openshift/odo/pkg/kclient"
+)
+
+// syncPipePath is a named pipe inside the builder pod's init container.
+// Blocking a read on it instead of polling a completion-marker file every
+// second removes the up-to-a-second handoff latency the old
+// "touch /tmp/complete" scheme paid on every build.
+const syncPipePath = "/tmp/sync-pipe"
+
+// initContainerHandoffScript is the init container's entrypoint: create the
+// named pipe and block until a single byte is written to it by
+// signalSyncComplete.
+const initContainerHandoffScript = "mkfifo " + syncPipePath + " && cat " + syncPipePath + " >/dev/null"
+
+// signalSyncComplete releases the init container's blocking read on
+// syncPipePath, handing the builder pod off from "receiving context" to
+// "building" without the old polling delay.
+func signalSyncComplete(client *kclient.Client, podName, containerName string) error {
+	compInfo := common.ComponentInfo{PodName: podName, ContainerName: containerName}
+	var stdout, stderr bytes.Buffer
+	return client.ExecCMDInContainer(compInfo, []string{"sh", "-c", "echo -n 1 > " + syncPipePath}, &stdout, &stderr, nil, false)
+}
@@ -0,0 +1,128 @@
+package build
+
+import (
+	"context"
+
+	"github.com/openshift/odo/pkg/kclient"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func init() {
+	Register(kanikoStrategy{})
+}
+
+const (
+	kanikoImage           = "gcr.io/kaniko-project/executor:latest"
+	kanikoSecret          = "kaniko-secret"
+	buildContext          = "build-context"
+	buildContextMountPath = "/root/build-context"
+	kanikoSecretMountPath = "/root/.docker"
+	kanikoContainerName   = "build"
+	kanikoInitContainer   = "init"
+	kanikoCacheVolume     = "kaniko-cache"
+	kanikoCacheMountPath  = "/cache"
+)
+
+var defaultId = int64(0)
+
+// kanikoStrategy builds images with https://github.com/GoogleContainerTools/kaniko,
+// the strategy odo has historically shipped.
+type kanikoStrategy struct{}
+
+func (kanikoStrategy) Name() string { return "kaniko" }
+
+func (k kanikoStrategy) Build(ctx context.Context, params Parameters) error {
+	return runBuild(ctx, params, "Kaniko", kanikoInitContainer, kanikoContainerName, k.createBuilderPod)
+}
+
+func (k kanikoStrategy) createBuilderPod(params Parameters, labels map[string]string) error {
+	objectMeta := kclient.CreateObjectMeta(params.ComponentName, params.Client.Namespace, labels, nil)
+	volumes := []corev1.Volume{
+		{
+			Name: buildContext,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: buildContext, MountPath: buildContextMountPath},
+	}
+	commandArgs := []string{
+		"--dockerfile=" + buildContextMountPath + "/Dockerfile",
+		"--context=dir://" + buildContextMountPath,
+		"--destination=" + params.BuildParameters.Tag,
+	}
+	envVars := []corev1.EnvVar{}
+
+	if params.Cache.Enabled {
+		commandArgs = append(commandArgs, "--cache=true")
+		if params.Cache.Repo != "" {
+			commandArgs = append(commandArgs, "--cache-repo="+params.Cache.Repo)
+		} else {
+			pvcName, err := ensureCachePVC(params.Client, params.ComponentName, params.Cache)
+			if err != nil {
+				return err
+			}
+			volumes = append(volumes, corev1.Volume{
+				Name: kanikoCacheVolume,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: kanikoCacheVolume, MountPath: kanikoCacheMountPath})
+			commandArgs = append(commandArgs, "--cache-dir="+kanikoCacheMountPath)
+		}
+	}
+
+	if params.SecretName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: kanikoSecret,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: params.SecretName,
+					Items: []corev1.KeyToPath{
+						{Key: ".dockerconfigjson", Path: "config.json"},
+					},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: kanikoSecret, MountPath: kanikoSecretMountPath})
+		envVars = append(envVars, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: kanikoSecretMountPath})
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: objectMeta,
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: params.ServiceAccountName,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsUser: &defaultId,
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name:            kanikoInitContainer,
+					Image:           "busybox",
+					ImagePullPolicy: corev1.PullAlways,
+					Command:         []string{"/bin/sh", "-c"},
+					Args:            []string{initContainerHandoffScript},
+					VolumeMounts:    []corev1.VolumeMount{{Name: buildContext, MountPath: buildContextMountPath}},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            kanikoContainerName,
+					Image:           kanikoImage,
+					ImagePullPolicy: corev1.PullAlways,
+					Env:             envVars,
+					Args:            commandArgs,
+					VolumeMounts:    volumeMounts,
+				},
+			},
+			Volumes: volumes,
+		},
+	}
+
+	_, err := params.Client.KubeClient.CoreV1().Pods(params.Client.Namespace).Create(pod)
+	return err
+}
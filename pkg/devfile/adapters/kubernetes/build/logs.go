@@ -0,0 +1,135 @@
+package build
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/odo/pkg/kclient"
+	"github.com/openshift/odo/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// containerPollInterval is how often waitForContainerRunning polls the pod
+// while waiting for the builder container to start.
+const containerPollInterval = 1 * time.Second
+
+// waitAndGetPodOnInitContainerStarted waits for initContainerName in the
+// pod matching watchOptions to start, racing the blocking client call
+// against ctx the same way the completion wait at the end of runBuild
+// already does - so a SIGINT delivered while the pod is stuck Pending
+// (unschedulable, image pull backoff, ...) is honored immediately instead
+// of only once the watch eventually returns on its own.
+func waitAndGetPodOnInitContainerStarted(ctx context.Context, client *kclient.Client, watchOptions metav1.ListOptions, initContainerName string) (*corev1.Pod, error) {
+	type result struct {
+		pod *corev1.Pod
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		pod, err := client.WaitAndGetPodOnInitContainerStarted(watchOptions, initContainerName, "Waiting for component to start", false)
+		done <- result{pod: pod, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-done:
+		return r.pod, r.err
+	}
+}
+
+// waitForContainerRunning blocks until containerName within podName has
+// transitioned to Running or Terminated, or ctx is cancelled.
+func waitForContainerRunning(ctx context.Context, client *kclient.Client, podName, containerName string) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		pod, err := client.KubeClient.CoreV1().Pods(client.Namespace).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != containerName {
+				continue
+			}
+			if status.State.Running != nil || status.State.Terminated != nil {
+				return nil
+			}
+		}
+
+		time.Sleep(containerPollInterval)
+	}
+}
+
+// logTailSize caps how much of the builder container's log output is kept
+// in memory, so a failed build's error can include recent output without
+// holding the whole log for long-running builds.
+const logTailSize = 16 * 1024
+
+// logTail is a small rolling buffer of the most recently streamed log
+// lines, bounded by logTailSize bytes, used to enrich build-failure
+// errors with diagnostic output.
+type logTail struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+}
+
+func (t *logTail) append(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	t.size += len(line)
+	for t.size > logTailSize && len(t.lines) > 0 {
+		t.size -= len(t.lines[0])
+		t.lines = t.lines[1:]
+	}
+}
+
+func (t *logTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.Join(t.lines, "\n")
+}
+
+// streamLogs follows containerName's log output in podName, prefixing
+// every line with "[build]" and writing it to stdout unless quiet is set.
+// It keeps a rolling tail of the most recent output so a caller can
+// surface it in an error if the build fails, and stops following once ctx
+// is cancelled or the stream closes.
+func streamLogs(ctx context.Context, client *kclient.Client, podName, containerName string, quiet bool) *logTail {
+	tail := &logTail{}
+	go func() {
+		stream, err := client.KubeClient.CoreV1().Pods(client.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+			Container: containerName,
+			Follow:    true,
+		}).Stream()
+		if err != nil {
+			return
+		}
+		defer stream.Close()
+
+		go func() {
+			<-ctx.Done()
+			stream.Close()
+		}()
+
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			line := scanner.Text()
+			tail.append(line)
+			if !quiet {
+				log.Info("[build] " + line)
+			}
+		}
+	}()
+	return tail
+}
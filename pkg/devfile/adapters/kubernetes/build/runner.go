@@ -0,0 +1,73 @@
+package build
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/odo/pkg/log"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runBuild is the orchestration every Strategy shares: create the builder
+// pod, wait for its init container, hand the build context off to it,
+// wait for the build container to start, stream its logs, and block until
+// the pod finishes or ctx is cancelled. If ctx is cancelled at any point
+// after the pod is created, the pod is deleted before runBuild returns. A Strategy supplies only its pod spec (via createPod) and its
+// init/main container names, so adding a new builder never means touching
+// this sequence.
+func runBuild(ctx context.Context, params Parameters, strategyLabel, initContainerName, mainContainerName string, createPod func(params Parameters, labels map[string]string) error) error {
+	labels := map[string]string{
+		"component": params.ComponentName,
+	}
+
+	if err := createPod(params, labels); err != nil {
+		return errors.Wrapf(err, "error while creating %s builder pod", strategyLabel)
+	}
+	defer func() {
+		if ctx.Err() != nil {
+			_ = params.Client.KubeClient.CoreV1().Pods(params.Client.Namespace).Delete(params.ComponentName, &metav1.DeleteOptions{})
+		}
+	}()
+
+	podSelector := fmt.Sprintf("component=%s", params.ComponentName)
+	watchOptions := metav1.ListOptions{LabelSelector: podSelector}
+
+	pod, err := waitAndGetPodOnInitContainerStarted(ctx, params.Client, watchOptions, initContainerName)
+	if err != nil {
+		return errors.Wrapf(err, "error while waiting for pod %s", podSelector)
+	}
+
+	if err := params.Sync(pod.GetName(), initContainerName); err != nil {
+		return errors.Wrapf(err, "failed to sync to component with name %s", params.ComponentName)
+	}
+	if err := signalSyncComplete(params.Client, pod.GetName(), initContainerName); err != nil {
+		return errors.Wrap(err, "failed to hand off build context to the builder pod")
+	}
+
+	log.Successf("Started builder pod %s using %s Build strategy", pod.GetName(), strategyLabel)
+
+	if err := waitForContainerRunning(ctx, params.Client, pod.GetName(), mainContainerName); err != nil {
+		return errors.Wrap(err, "error while waiting for the builder container to start")
+	}
+	tail := streamLogs(ctx, params.Client, pod.GetName(), mainContainerName, params.Quiet)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := params.Client.WaitAndGetPod(watchOptions, corev1.PodSucceeded, "Waiting for builder pod to complete", false)
+		done <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return errors.Wrapf(err, "unable to build image using %s, recent build output:\n%s", strategyLabel, tail)
+		}
+	}
+
+	log.Successf("Successfully built container image: %s", params.BuildParameters.Tag)
+	return nil
+}
@@ -1,25 +1,32 @@
 package component
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"reflect"
-	"strconv"
 	"strings"
+	stdsync "sync"
 	"syscall"
-	"text/template"
 	"time"
 
 	componentlabels "github.com/openshift/odo/pkg/component/labels"
+	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/build"
+	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/render"
+	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/wait"
 	"github.com/openshift/odo/pkg/envinfo"
 	"github.com/openshift/odo/pkg/exec"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8srand "k8s.io/apimachinery/pkg/util/rand"
 
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
@@ -29,6 +36,7 @@ import (
 	"k8s.io/klog"
 
 	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/odo/pkg/auth"
 	"github.com/openshift/odo/pkg/component"
 	"github.com/openshift/odo/pkg/config"
 	"github.com/openshift/odo/pkg/devfile/adapters/common"
@@ -46,6 +54,7 @@ import (
 
 const (
 	regcredName           = "regcred"
+	dockerConfigJSONKey   = ".dockerconfigjson"
 	DeployComponentSuffix = "-deploy"
 	BuildTimeout          = 5 * time.Minute
 	internalRegistryHost  = "image-registry.openshift-image-registry.svc:5000"
@@ -104,12 +113,30 @@ func (a Adapter) runBuildConfig(client *occlient.Client, parameters common.Build
 	var secretName string
 	if !isImageRegistryInternal {
 		secretName = regcredName
+		if err := a.createDockerConfigSecret(parameters, secretName); err != nil {
+			return err
+		}
 	}
-	_, err = client.CreateDockerBuildConfigWithBinaryInput(commonObjectMeta, dockerfilePath, parameters.Tag, []corev1.EnvVar{}, buildOutput, secretName)
+
+	buildConfig, err := client.CreateDockerBuildConfigWithBinaryInput(commonObjectMeta, dockerfilePath, parameters.Tag, []corev1.EnvVar{}, buildOutput, secretName)
 	if err != nil {
 		return err
 	}
 
+	if secretName != "" {
+		// Own the credentials Secret by the BuildConfig so an interrupted build
+		// (see terminateBuild) doesn't leave it behind once the BuildConfig it
+		// was created for is gone.
+		if err := a.setSecretOwner(secretName, metav1.OwnerReference{
+			APIVersion: buildConfig.APIVersion,
+			Kind:       buildConfig.Kind,
+			Name:       buildConfig.Name,
+			UID:        buildConfig.UID,
+		}); err != nil {
+			return err
+		}
+	}
+
 	defer func() {
 		// This will delete both the BuildConfig and any builds using that BuildConfig
 		derr := client.DeleteBuildConfig(commonObjectMeta)
@@ -182,12 +209,11 @@ func (a Adapter) Build(parameters common.BuildParameters) (err error) {
 		return err
 	}
 
-	if !isImageRegistryInternal {
-		if err := a.createDockerConfigSecret(parameters); err != nil {
-			return err
-		}
-	}
-
+	// Both build paths resolve their own registry credentials Secret:
+	// runBuildConfig creates one scoped to the BuildConfig (see
+	// createDockerConfigSecret), while the Kaniko/BuildKit/Buildah
+	// strategies resolve a richer one via runKaniko/build.ResolveSecret
+	// (ServiceAccount discovery, multi-registry merge).
 	if isBuildConfigSupported && !parameters.Rootless {
 		return a.runBuildConfig(client, parameters, isImageRegistryInternal)
 	} else {
@@ -195,21 +221,41 @@ func (a Adapter) Build(parameters common.BuildParameters) (err error) {
 	}
 }
 
-// Perform the substitutions in the manifest file(s)
-func substitueYamlVariables(baseYaml []byte, yamlSubstitutions map[string]string) ([]byte, error) {
-	// create new template from parsing file
-	tmpl, err := template.New("deploy").Parse(string(baseYaml))
+// deployValues builds the merged value set exposed to deploy manifest
+// templates as .Values, in increasing order of precedence: built-in values
+// (image, component, port, namespace, labels), devfile-provided values,
+// .odo/values.yaml, and finally --set/--set-file flags.
+func (a Adapter) deployValues(parameters common.DeployParameters, applicationName, namespace string) (render.Values, error) {
+	builtin := render.Values{
+		"image":     parameters.Tag,
+		"component": applicationName,
+		"port":      parameters.DeploymentPort,
+		"namespace": namespace,
+		"labels": render.Values{
+			"component": applicationName,
+		},
+	}
+
+	// devfile v1 (the schema this adapter works with) has no top-level
+	// "attributes" hook equivalent to devfile v2's `attributes.deploy`, so
+	// this value source is empty until the adapter moves to v2.
+	devfileValues := render.Values{}
+
+	fileValues, err := render.LoadValuesFile(filepath.Join(a.Context, ".odo", "values.yaml"))
 	if err != nil {
-		return []byte{}, errors.Wrap(err, "error creating template")
+		return nil, errors.Wrap(err, "unable to load .odo/values.yaml")
 	}
 
-	// define a buffer to store the results
-	var buf bytes.Buffer
-
-	// apply template to yaml file
-	_ = tmpl.Execute(&buf, yamlSubstitutions)
+	setValues, err := render.ParseSet(parameters.SetValues)
+	if err != nil {
+		return nil, err
+	}
+	setFileValues, err := render.ParseSetFile(parameters.SetFiles)
+	if err != nil {
+		return nil, err
+	}
 
-	return buf.Bytes(), nil
+	return render.Merge(builtin, devfileValues, fileValues, setValues, setFileValues), nil
 }
 
 // Build image for devfile project
@@ -238,11 +284,19 @@ func (a Adapter) Deploy(parameters common.DeployParameters) (err error) {
 		parameters.Tag = imageStreamImage.Image.DockerImageReference
 	}
 
-	// Specify the substitution keys and values
-	yamlSubstitutions := map[string]string{
-		"CONTAINER_IMAGE": parameters.Tag,
-		"COMPONENT_NAME":  applicationName,
-		"PORT":            strconv.Itoa(parameters.DeploymentPort),
+	values, err := a.deployValues(parameters, applicationName, namespace)
+	if err != nil {
+		return err
+	}
+
+	renderedManifest, err := render.Render(parameters.ManifestSource, values)
+	if err != nil {
+		return errors.Wrap(err, "unable to render deploy manifest")
+	}
+
+	if parameters.DryRun {
+		log.Info(string(renderedManifest))
+		return nil
 	}
 
 	// Build a yaml decoder with the unstructured Scheme
@@ -263,16 +317,25 @@ func (a Adapter) Deploy(parameters common.DeployParameters) (err error) {
 		}
 	}()
 
-	manifests := bytes.Split(parameters.ManifestSource, []byte("---"))
+	var appliedTargets []wait.Target
+
+	// Server-Side Apply lets the API server three-way-merge each manifest against
+	// the fields every other field manager (GitOps tooling, controllers, ...) owns,
+	// instead of odo clobbering them with a blind Update. Older clusters don't speak
+	// it, so fall back to the Get/merge/retry dance below when it isn't available.
+	ssaSupported, ssaErr := client.IsServerSideApplySupported()
+	if ssaErr != nil {
+		klog.V(3).Infof("unable to determine if the cluster supports server-side apply, falling back to Get/merge/Update: %v", ssaErr)
+		ssaSupported = false
+	}
+	force := parameters.ForceApply
+
+	// Manifests are split only after rendering the whole stream (see render.Render),
+	// so a "---" produced by a template action separates documents just like a literal one.
+	manifests := bytes.Split(renderedManifest, []byte("---"))
 	for _, manifest := range manifests {
 		if len(manifest) > 0 {
-			// Substitute the values in the manifest file
-			deployYaml, err := substitueYamlVariables(manifest, yamlSubstitutions)
-			if err != nil {
-				return errors.Wrap(err, "unable to substitute variables in manifest")
-			}
-
-			_, gvk, err := yamlDecoder.Decode([]byte(deployYaml), nil, deploymentManifest)
+			_, gvk, err := yamlDecoder.Decode(manifest, nil, deploymentManifest)
 			if err != nil {
 				return errors.New("Failed to decode the manifest yaml")
 			}
@@ -295,42 +358,68 @@ func (a Adapter) Deploy(parameters common.DeployParameters) (err error) {
 				deploymentManifest.SetLabels(labels)
 			}
 
-			// Check to see whether deployed resource already exists. If not, create else update
-			instanceFound := false
-			item, err := a.Client.DynamicClient.Resource(gvr).Namespace(namespace).Get(deploymentManifest.GetName(), metav1.GetOptions{})
-			if item != nil && err == nil {
-				instanceFound = true
-				deploymentManifest.SetResourceVersion(item.GetResourceVersion())
-				deploymentManifest.SetAnnotations(item.GetAnnotations())
-				// If deployment is a `Service` of type `ClusterIP` then the service in the manifest will probably not
-				// have a ClusterIP defined, as this is determined when the manifest is applied. When updating the Service
-				// the manifest cannot have an empty `ClusterIP` defintion, so we need to copy this from the existing definition.
-				if item.GetKind() == "Service" {
-					currentServiceSpec := item.UnstructuredContent()["spec"].(map[string]interface{})
-					if currentServiceSpec["clusterIP"] != nil && currentServiceSpec["clusterIP"] != "" {
-						newService := deploymentManifest.UnstructuredContent()
-						newService["spec"].(map[string]interface{})["clusterIP"] = currentServiceSpec["clusterIP"]
-						deploymentManifest.SetUnstructuredContent(newService)
+			var result *unstructured.Unstructured
+			if ssaSupported {
+				s := log.Spinnerf("Applying resource of kind %s", gvk.Kind)
+				patchData, perr := deploymentManifest.MarshalJSON()
+				if perr != nil {
+					s.End(false)
+					return errors.Wrapf(perr, "unable to marshal manifest %s", gvk.Kind)
+				}
+				err = utils.Retry(utils.DefaultRetryOptions, func(attempt int) error {
+					var patchErr error
+					result, patchErr = a.Client.DynamicClient.Resource(gvr).Namespace(namespace).Patch(
+						deploymentManifest.GetName(), types.ApplyPatchType, patchData,
+						metav1.PatchOptions{FieldManager: "odo", Force: &force},
+					)
+					return patchErr
+				})
+				if err != nil {
+					s.End(false)
+					return errors.Wrapf(err, "Failed to apply manifest %s", gvk.Kind)
+				}
+				s.End(true)
+			} else {
+				// Check to see whether deployed resource already exists, just to pick the right
+				// spinner label; the actual create/update below re-checks this itself on every retry.
+				instanceFound := false
+				if item, err := a.Client.DynamicClient.Resource(gvr).Namespace(namespace).Get(deploymentManifest.GetName(), metav1.GetOptions{}); item != nil && err == nil {
+					instanceFound = true
+				}
+
+				actionType := "Creating"
+				if instanceFound {
+					actionType = "Updating" // Update deployment
+				}
+				s := log.Spinnerf("%s resource of kind %s", strings.Title(actionType), gvk.Kind)
+				result, err = utils.CreateOrUpdate(a.Client.DynamicClient.Resource(gvr).Namespace(namespace), deploymentManifest, func(live, desired *unstructured.Unstructured) {
+					desired.SetAnnotations(live.GetAnnotations())
+					// If deployment is a `Service` of type `ClusterIP` then the service in the manifest will probably not
+					// have a ClusterIP defined, as this is determined when the manifest is applied. When updating the Service
+					// the manifest cannot have an empty `ClusterIP` defintion, so we need to copy this from the existing definition.
+					if live.GetKind() == "Service" {
+						if liveSpec, ok := live.UnstructuredContent()["spec"].(map[string]interface{}); ok {
+							if clusterIP, ok := liveSpec["clusterIP"].(string); ok && clusterIP != "" {
+								if desiredSpec, ok := desired.UnstructuredContent()["spec"].(map[string]interface{}); ok {
+									desiredSpec["clusterIP"] = clusterIP
+								}
+							}
+						}
 					}
+				}, utils.DefaultRetryOptions)
+				if err != nil {
+					s.End(false)
+					return errors.Wrapf(err, "Failed when %s manifest %s", actionType, gvk.Kind)
 				}
+				s.End(true)
 			}
 
-			actionType := "Creating"
-			if instanceFound {
-				actionType = "Updating" // Update deployment
-			}
-			s := log.Spinnerf("%s resource of kind %s", strings.Title(actionType), gvk.Kind)
-			var result *unstructured.Unstructured
-			if !instanceFound {
-				result, err = a.Client.DynamicClient.Resource(gvr).Namespace(namespace).Create(deploymentManifest, metav1.CreateOptions{})
-			} else {
-				result, err = a.Client.DynamicClient.Resource(gvr).Namespace(namespace).Update(deploymentManifest, metav1.UpdateOptions{})
-			}
-			if err != nil {
-				s.End(false)
-				return errors.Wrapf(err, "Failed when %s manifest %s", actionType, gvk.Kind)
-			}
-			s.End(true)
+			appliedTargets = append(appliedTargets, wait.Target{
+				GVR:       gvr,
+				Namespace: namespace,
+				Name:      result.GetName(),
+				Kind:      gvk.Kind,
+			})
 
 			if imageStream != nil {
 				ownerReference := metav1.OwnerReference{
@@ -364,11 +453,15 @@ func (a Adapter) Deploy(parameters common.DeployParameters) (err error) {
 			return err
 		}
 	}
-	s := log.Spinner("Determining the application URL")
+	waitTimeout := parameters.WaitTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = wait.DefaultTimeout
+	}
+	if err := wait.New(&a.Client).Wait(context.Background(), appliedTargets, waitTimeout); err != nil {
+		return errors.Wrap(err, "deployed resources did not become ready")
+	}
 
-	// Need to wait for a second to give the server time to create the artifacts
-	// TODO: Replace wait with a wait for object to be created correctly
-	time.Sleep(2 * time.Second)
+	s := log.Spinner("Determining the application URL")
 
 	labelSelector := fmt.Sprintf("%v=%v", "component", applicationName)
 	fullURL, err := client.GetApplicationURL(applicationName, labelSelector)
@@ -405,7 +498,7 @@ func (a Adapter) DeployDelete(manifest []byte) (err error) {
 				return errors.New(errorMessage)
 			}
 
-			err = a.Client.DynamicClient.Resource(gvr).Namespace(a.Client.Namespace).Delete(deploymentManifest.GetName(), &metav1.DeleteOptions{})
+			err = utils.DeleteWithRetry(a.Client.DynamicClient.Resource(gvr).Namespace(a.Client.Namespace), deploymentManifest.GetName(), utils.DefaultRetryOptions)
 			if err != nil {
 				return err
 			}
@@ -599,6 +692,12 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 
 	kclient.AddBootstrapSupervisordInitContainer(podTemplateSpec)
 
+	preStartInitContainers, err := utils.GenerateInitContainers(a.Devfile, containers)
+	if err != nil {
+		return errors.Wrap(err, "unable to generate init containers for preStart event commands")
+	}
+	podTemplateSpec.Spec.InitContainers = append(podTemplateSpec.Spec.InitContainers, preStartInitContainers...)
+
 	containerNameToVolumes := common.GetVolumes(a.Devfile)
 
 	var uniqueStorages []common.Storage
@@ -670,34 +769,36 @@ func (a Adapter) createOrUpdateComponent(componentExists bool, ei envinfo.EnvSpe
 			return err
 		}
 		klog.V(4).Infof("Successfully updated component %v", componentName)
-		oldSvc, err := a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Get(componentName, metav1.GetOptions{})
-		objectMetaTemp := objectMeta
-		ownerReference := kclient.GenerateOwnerReference(deployment)
-		objectMetaTemp.OwnerReferences = append(objectMeta.OwnerReferences, ownerReference)
-		if err != nil {
-			// no old service was found, create a new one
-			if len(serviceSpec.Ports) > 0 {
-				_, err = a.Client.CreateService(objectMetaTemp, *serviceSpec)
-				if err != nil {
-					return err
+		// Retry on a conflicting ResourceVersion: re-Get the Service and re-apply the
+		// ownerRef/clusterIP onto it before retrying the Update.
+		err = utils.Retry(utils.DefaultRetryOptions, func(attempt int) error {
+			oldSvc, err := a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Get(componentName, metav1.GetOptions{})
+			objectMetaTemp := objectMeta
+			ownerReference := kclient.GenerateOwnerReference(deployment)
+			objectMetaTemp.OwnerReferences = append(objectMeta.OwnerReferences, ownerReference)
+			if err != nil {
+				// no old service was found, create a new one
+				if len(serviceSpec.Ports) > 0 {
+					if _, err := a.Client.CreateService(objectMetaTemp, *serviceSpec); err != nil {
+						return err
+					}
+					klog.V(4).Infof("Successfully created Service for component %s", componentName)
 				}
-				klog.V(4).Infof("Successfully created Service for component %s", componentName)
+				return nil
 			}
-		} else {
 			if len(serviceSpec.Ports) > 0 {
 				serviceSpec.ClusterIP = oldSvc.Spec.ClusterIP
 				objectMetaTemp.ResourceVersion = oldSvc.GetResourceVersion()
-				_, err = a.Client.UpdateService(objectMetaTemp, *serviceSpec)
-				if err != nil {
+				if _, err := a.Client.UpdateService(objectMetaTemp, *serviceSpec); err != nil {
 					return err
 				}
 				klog.V(4).Infof("Successfully update Service for component %s", componentName)
-			} else {
-				err = a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Delete(componentName, &metav1.DeleteOptions{})
-				if err != nil {
-					return err
-				}
+				return nil
 			}
+			return a.Client.KubeClient.CoreV1().Services(a.Client.Namespace).Delete(componentName, &metav1.DeleteOptions{})
+		})
+		if err != nil {
+			return err
 		}
 	} else {
 		deployment, err := a.Client.CreateDeployment(*deploymentSpec)
@@ -763,7 +864,7 @@ func (a Adapter) execDevfile(commandsMap common.PushCommandsMap, componentExists
 		command, ok := commandsMap[versionsCommon.InitCommandGroupType]
 		if ok {
 			if command.Composite != nil {
-				err = exec.ExecuteCompositeDevfileAction(&a.Client, *command.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
+				err = a.executeComposite(*command.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
 				if err != nil {
 					return err
 				}
@@ -783,7 +884,7 @@ func (a Adapter) execDevfile(commandsMap common.PushCommandsMap, componentExists
 	command, ok := commandsMap[versionsCommon.BuildCommandGroupType]
 	if ok {
 		if command.Composite != nil {
-			err = exec.ExecuteCompositeDevfileAction(&a.Client, *command.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
+			err = a.executeComposite(*command.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
 			if err != nil {
 				return err
 			}
@@ -837,6 +938,24 @@ func (a Adapter) execDevfile(commandsMap common.PushCommandsMap, componentExists
 	return
 }
 
+// executeComposite runs composite by delegating to
+// exec.ExecuteCompositeDevfileAction, which runs its children serially.
+//
+// composite.Parallel is not honored: concurrent execution needs a
+// context-aware synchronous exec entrypoint in pkg/exec and a
+// logger.Child(prefix) factory on machineoutput.MachineEventLoggingClient,
+// and neither exists in this tree. Rather than silently running a parallel
+// composite serially (which changes its failure/ordering semantics without
+// telling the caller), reject it up front the same way
+// utils.GenerateInitContainers rejects a parallel preStart composite. This
+// is a known gap, not a follow-up that's already been scheduled.
+func (a Adapter) executeComposite(composite versionsCommon.Composite, commandMap map[string]versionsCommon.DevfileCommand, compInfo common.ComponentInfo, show bool, logger machineoutput.MachineEventLoggingClient) error {
+	if composite.Parallel {
+		return fmt.Errorf("composite command %q requests parallel execution, which is not yet supported; its children would run serially instead of concurrently", composite.Id)
+	}
+	return exec.ExecuteCompositeDevfileAction(&a.Client, composite, commandMap, compInfo, show, logger)
+}
+
 // execDevfileEvent receives a Devfile Event (PostStart, PreStop etc.) and loops through them
 // Each Devfile Command associated with the given event is retrieved, and executed in the container specified
 // in the command
@@ -852,7 +971,7 @@ func (a Adapter) execDevfileEvent(events []string, podName string) error {
 		}
 
 		if command.Composite != nil {
-			err := exec.ExecuteCompositeDevfileAction(&a.Client, *command.Composite, commandMap, compInfo, false, a.machineEventLogger)
+			err := a.executeComposite(*command.Composite, commandMap, compInfo, false, a.machineEventLogger)
 			if err != nil {
 				return errors.Wrapf(err, "unable to execute devfile composite command "+commandName)
 			}
@@ -879,7 +998,7 @@ func (a Adapter) execTestCmd(testCmd versionsCommon.DevfileCommand, podName stri
 		// Need to get mapping of all commands in the devfile since the composite command may reference any exec or composite command in the devfile
 		devfileCommandMap := common.GetCommandsMap(a.Devfile.Data.GetCommands())
 
-		err = exec.ExecuteCompositeDevfileAction(&a.Client, *testCmd.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
+		err = a.executeComposite(*testCmd.Composite, devfileCommandMap, compInfo, show, a.machineEventLogger)
 	} else {
 		compInfo.ContainerName = testCmd.Exec.Component
 		err = exec.ExecuteDevfileCommandSynchronously(&a.Client, *testCmd.Exec, testCmd.Exec.Id, compInfo, show, a.machineEventLogger, false)
@@ -987,6 +1106,163 @@ func (a Adapter) Log(follow, debug bool) (io.ReadCloser, error) {
 	return a.Client.GetPodLogs(pod.Name, containerName, follow)
 }
 
+const (
+	logStreamMinBackoff = 1 * time.Second
+	logStreamMaxBackoff = 30 * time.Second
+)
+
+// LogAll returns a merged, line-prefixed log stream covering every
+// container in the component's pod, or containerFilter's subset of them
+// when non-empty, unlike Log which only ever streams the single container
+// the run/debug command targets. This matters for devfiles that declare
+// more than one mountSources: true component (sidecars, DB helpers, ...),
+// where Log misses half the debugging signal.
+//
+// Each container gets its own GetPodLogs stream and goroutine; every line
+// is tagged "[<container>] " before being written into a single io.Pipe
+// that the returned io.ReadCloser reads from. With follow=true, a stream
+// that errors out (e.g. its container restarting) is retried with
+// exponential backoff instead of tearing down its siblings, and a
+// "[<container>] --- restarted ---" marker is written to the merged
+// output before each retry after the first.
+func (a Adapter) LogAll(follow, debug bool, containerFilter []string) (io.ReadCloser, error) {
+	pod, err := a.Client.GetPodUsingComponentName(a.ComponentName)
+	if err != nil {
+		return nil, errors.Errorf("the component %s doesn't exist on the cluster", a.ComponentName)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, errors.Errorf("unable to show logs, component is not in running state. current status=%v", pod.Status.Phase)
+	}
+
+	containerNames := filterContainerNames(pod.Spec.Containers, containerFilter)
+	if len(containerNames) == 0 {
+		return nil, fmt.Errorf("no containers in pod %s matched the requested filter %v", pod.Name, containerFilter)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	var wg stdsync.WaitGroup
+	for _, containerName := range containerNames {
+		wg.Add(1)
+		go func(containerName string) {
+			defer wg.Done()
+			a.streamContainerLogs(pod.Name, containerName, follow, pipeWriter)
+		}(containerName)
+	}
+	go func() {
+		wg.Wait()
+		pipeWriter.Close()
+	}()
+
+	return pipeReader, nil
+}
+
+// filterContainerNames returns the names of every container in containers,
+// or only those named in filter when it's non-empty.
+func filterContainerNames(containers []corev1.Container, filter []string) []string {
+	if len(filter) == 0 {
+		names := make([]string, 0, len(containers))
+		for _, c := range containers {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	allowed := make(map[string]bool, len(filter))
+	for _, name := range filter {
+		allowed[name] = true
+	}
+	var names []string
+	for _, c := range containers {
+		if allowed[c.Name] {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// streamContainerLogs copies containerName's log stream into dest, with
+// every line tagged "[<container>] ". With follow=true, the stream ending -
+// whether from an error or a clean EOF - is retried with exponential
+// backoff (capped at logStreamMaxBackoff) as long as containerName is still
+// live, writing a "[<container>] --- restarted ---" marker into dest before
+// each retry after the first attempt. A clean EOF is the ordinary way a log
+// stream ends when its container restarts, so it's treated the same as a
+// stream error rather than as a signal to stop following. With follow=true,
+// streamContainerLogs returns once containerName has actually terminated for
+// good, or once dest itself rejects the restart marker (the reader went
+// away). With follow=false, it returns as soon as its single stream ends.
+func (a Adapter) streamContainerLogs(podName, containerName string, follow bool, dest io.Writer) {
+	prefix := fmt.Sprintf("[%s] ", containerName)
+	backoff := logStreamMinBackoff
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if _, err := fmt.Fprintf(dest, "%s--- restarted ---\n", prefix); err != nil {
+				return
+			}
+		}
+
+		stream, err := a.Client.GetPodLogs(podName, containerName, follow)
+		if err == nil {
+			err = copyPrefixedLines(dest, stream, prefix)
+			stream.Close()
+		}
+		if !follow {
+			if err != nil {
+				fmt.Fprintf(dest, "%serror: %v\n", prefix, err)
+			}
+			return
+		}
+		if a.containerHasTerminated(podName, containerName) {
+			return
+		}
+
+		klog.V(4).Infof("log stream for container %s ended, retrying in %s: %v", containerName, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > logStreamMaxBackoff {
+			backoff = logStreamMaxBackoff
+		}
+	}
+}
+
+// containerHasTerminated reports whether containerName is done for good -
+// its pod is gone, the pod has finished (Succeeded/Failed), or the
+// container no longer appears in the pod's status - as opposed to merely
+// having ended its current log stream while the kubelet is about to (or
+// already did) restart it. streamContainerLogs uses this to tell a clean
+// EOF caused by a restart apart from the stream's real, final end.
+func (a Adapter) containerHasTerminated(podName, containerName string) bool {
+	pod, err := a.Client.GetPodUsingComponentName(a.ComponentName)
+	if err != nil || pod.Name != podName {
+		return true
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return false
+		}
+	}
+	return true
+}
+
+// copyPrefixedLines copies every line of src into dest with prefix
+// prepended, so the concurrently interleaved output of several containers
+// stays attributable to the one that produced each line.
+func copyPrefixedLines(dest io.Writer, src io.Reader, prefix string) error {
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintf(dest, "%s%s\n", prefix, scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
 // Exec executes a command in the component
 func (a Adapter) Exec(command []string) error {
 	exists, err := utils.ComponentExists(a.Client, a.ComponentName)
@@ -1022,20 +1298,241 @@ func (a Adapter) Exec(command []string) error {
 	return exec.ExecuteCommand(&a.Client, componentInfo, command, true, nil, nil)
 }
 
-func (a Adapter) createDockerConfigSecret(parameters common.BuildParameters) error {
-	data, err := utils.CreateDockerConfigDataFromFilepath(parameters.DockerConfigJSONFilename)
+// DebugOptions configures Adapter.Debug.
+type DebugOptions struct {
+	// Image is the ephemeral debug container's image. Defaults to the
+	// devfile run container's image when empty.
+	Image string
+	// Profile selects a SecurityContext preset for the debug container:
+	// "general" (the default, no elevated privileges), "restricted"
+	// (RunAsNonRoot, all capabilities dropped), or "netadmin" (adds
+	// NET_ADMIN/NET_RAW for tools like tcpdump).
+	Profile string
+}
+
+const (
+	debugProfileGeneral    = "general"
+	debugProfileRestricted = "restricted"
+	debugProfileNetadmin   = "netadmin"
+)
+
+// Debug attaches an ephemeral debug container to the running component pod,
+// the way `kubectl debug` does, without restarting or otherwise modifying
+// it, then attaches the user's terminal to it. This gives users an
+// interactive shell with tools like curl, strace or tcpdump available even
+// in a distroless component image.
+//
+// No `odo debug attach` subcommand exists in this tree: the CLI command
+// tree (pkg/odo/cli/...) that the original request asked to expose this
+// feature through isn't part of this checkout, so there's nothing here to
+// wire it into. This remains library-only until that CLI package lands.
+func (a Adapter) Debug(opts DebugOptions) error {
+	runCommand, err := common.GetRunCommand(a.Devfile.Data, "")
+	if err != nil {
+		return err
+	}
+	targetContainerName := runCommand.Exec.Component
+
+	pod, err := a.Client.GetPodUsingComponentName(a.ComponentName)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get pod for component %s", a.ComponentName)
+	}
+
+	targetContainer, err := containerByName(pod.Spec.Containers, targetContainerName)
+	if err != nil {
+		return err
+	}
+
+	image := opts.Image
+	if image == "" {
+		image = targetContainer.Image
+	}
+
+	debugContainerName := fmt.Sprintf("debugger-%s", k8srand.String(5))
+	patch := corev1.Pod{
+		Spec: corev1.PodSpec{
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{
+					EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+						Name:            debugContainerName,
+						Image:           image,
+						Env:             targetContainer.Env,
+						Stdin:           true,
+						TTY:             true,
+						SecurityContext: debugSecurityContext(opts.Profile),
+					},
+					TargetContainerName: targetContainerName,
+				},
+			},
+		},
+	}
+
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "unable to build ephemeral container patch")
+	}
+
+	if err := a.Client.PatchEphemeralContainer(pod.Name, patchBytes); err != nil {
+		return errors.Wrap(err, "unable to add debug container to pod")
+	}
+
+	log.Infof("\nWaiting for debug container %s to start", debugContainerName)
+	if err := waitForEphemeralContainer(&a.Client, pod.Name, debugContainerName); err != nil {
+		return err
+	}
+
+	compInfo := common.ComponentInfo{
+		PodName:       pod.Name,
+		ContainerName: debugContainerName,
+	}
+	return exec.ExecuteCommand(&a.Client, compInfo, []string{"sh"}, true, nil, nil)
+}
+
+// debugSecurityContext returns the SecurityContext for the given debug
+// profile name, defaulting to unrestricted (nil) for an unknown or empty
+// profile.
+func debugSecurityContext(profile string) *corev1.SecurityContext {
+	switch profile {
+	case debugProfileRestricted:
+		nonRoot := true
+		return &corev1.SecurityContext{
+			RunAsNonRoot: &nonRoot,
+			Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		}
+	case debugProfileNetadmin:
+		return &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_RAW"}},
+		}
+	case debugProfileGeneral, "":
+		return nil
+	default:
+		return nil
+	}
+}
+
+// containerByName returns a pointer to the container named name, so its
+// fields (e.g. Image, Env) can be inherited by a derived container.
+func containerByName(containers []corev1.Container, name string) (*corev1.Container, error) {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no container named %s found in pod", name)
+}
+
+const ephemeralContainerPollInterval = 1 * time.Second
+
+// waitForEphemeralContainer polls podName until containerName's ephemeral
+// container status reports Running or Terminated.
+func waitForEphemeralContainer(client *kclient.Client, podName, containerName string) error {
+	for {
+		pod, err := client.GetPod(podName)
+		if err != nil {
+			return err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != containerName {
+				continue
+			}
+			if status.State.Running != nil {
+				return nil
+			}
+			if status.State.Terminated != nil {
+				return fmt.Errorf("debug container %s terminated: %s", containerName, status.State.Terminated.Message)
+			}
+		}
+		time.Sleep(ephemeralContainerPollInterval)
+	}
+}
+
+// createDockerConfigSecret builds a merged kubernetes.io/dockerconfigjson
+// Secret named secretName for the BuildConfig push, covering the push
+// target's registry and every registry referenced by a FROM line in the
+// project's Dockerfile, resolved from parameters.DockerConfigJSONFilename or
+// else parameters.AuthConfigPath when set, falling back to the first local
+// docker/podman credential file auth.DefaultConfigPath finds, plus any explicit
+// --registry-auth registry=path/to/config.json entries.
+//
+// If parameters.RegistryAuthFromSecret is set, all of the above is skipped
+// and secretName instead becomes a copy of that existing in-cluster Secret.
+//
+// secretName is recreated on every push rather than reused, so the write
+// goes through utils.CreateOrUpdate to stay idempotent instead of a raw
+// Create that used to fail on the second push of the same component.
+func (a Adapter) createDockerConfigSecret(parameters common.BuildParameters, secretName string) error {
+	namespace := parameters.EnvSpecificInfo.GetNamespace()
+
+	if parameters.RegistryAuthFromSecret != "" {
+		return build.CopySecret(&a.Client, namespace, parameters.RegistryAuthFromSecret, secretName)
+	}
+
+	configPath := parameters.DockerConfigJSONFilename
+	if configPath == "" {
+		configPath = parameters.AuthConfigPath
+	}
+	if configPath == "" {
+		configPath = auth.DefaultConfigPath()
+	}
+
+	mergedData, err := auth.DockerConfigJSON(parameters.Tag, filepath.Join(a.Context, dockerfilePath), configPath)
+	if err != nil {
+		return errors.Wrap(err, "unable to load registry credentials")
+	}
+	var merged struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+	if err := json.Unmarshal(mergedData, &merged); err != nil {
+		return errors.Wrap(err, "unable to parse registry credentials")
+	}
+	if merged.Auths == nil {
+		merged.Auths = map[string]json.RawMessage{}
+	}
+
+	entries, err := build.ParseRegistryAuthFlags(parameters.RegistryAuthFlags)
 	if err != nil {
 		return err
 	}
-	secretUnstructured, err := utils.CreateSecret(regcredName, parameters.EnvSpecificInfo.GetNamespace(), data)
+	for _, entry := range entries {
+		cfg, err := auth.Load(entry.ConfigFile)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read --registry-auth config file %s for registry %s", entry.ConfigFile, entry.Registry)
+		}
+		resolved, ok, err := cfg.ResolveAuth(entry.Registry)
+		if err != nil {
+			return errors.Wrapf(err, "unable to resolve --registry-auth credentials for registry %s", entry.Registry)
+		}
+		if ok {
+			merged.Auths[entry.Registry] = resolved
+		}
+	}
+
+	mergedBytes, err := json.Marshal(merged)
 	if err != nil {
 		return err
 	}
-	if _, err := a.Client.DynamicClient.Resource(secretGroupVersionResource).
-		Namespace(parameters.EnvSpecificInfo.GetNamespace()).
-		Create(secretUnstructured, metav1.CreateOptions{}); err != nil {
+
+	secretUnstructured, err := utils.CreateSecret(secretName, namespace, map[string][]byte{dockerConfigJSONKey: mergedBytes})
+	if err != nil {
 		return err
 	}
+	_, err = utils.CreateOrUpdate(a.Client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace), secretUnstructured, nil, utils.DefaultRetryOptions)
+	return err
+}
+
+// setSecretOwner adds owner as an owner reference on the named Secret, so
+// deleting owner (e.g. a BuildConfig) garbage-collects the Secret too.
+func (a Adapter) setSecretOwner(secretName string, owner metav1.OwnerReference) error {
+	namespace := a.Client.Namespace
+	secret, err := a.Client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get registry credentials secret %s to set its garbage-collection owner", secretName)
+	}
+
+	secret.SetOwnerReferences(append(secret.GetOwnerReferences(), owner))
+	if _, err := a.Client.DynamicClient.Resource(secretGroupVersionResource).Namespace(namespace).Update(secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "unable to set garbage-collection owner on registry credentials secret %s", secretName)
+	}
 	return nil
 }
 
@@ -0,0 +1,120 @@
+package component
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/openshift/odo/pkg/devfile/adapters/kubernetes/build"
+	"github.com/openshift/odo/pkg/log"
+	"github.com/openshift/odo/pkg/sync"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const buildContextMountPath = "/root/build-context"
+
+var secretGroupVersionResource = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+// runKaniko builds and pushes the component image in-cluster using the
+// build strategy selected via parameters.BuildStrategy (Kaniko, BuildKit or
+// Buildah), defaulting to Kaniko for backwards compatibility.
+func (a Adapter) runKaniko(parameters common.BuildParameters, isImageRegistryInternal bool) (err error) {
+	strategy, err := build.Get(parameters.BuildStrategy)
+	if err != nil {
+		return err
+	}
+
+	var secretName string
+	if !isImageRegistryInternal {
+		entries, err := build.ParseRegistryAuthFlags(parameters.RegistryAuthFlags)
+		if err != nil {
+			return err
+		}
+
+		registryAuth := build.RegistryAuth{
+			SecretName:       regcredName,
+			ConfigFile:       parameters.DockerConfigJSONFilename,
+			ExtraConfigFiles: parameters.RegistryAuthConfigFiles,
+			ServiceAccount:   parameters.RegistryAuthServiceAccount,
+			PushTag:          parameters.Tag,
+			DockerfilePath:   filepath.Join(a.Context, dockerfilePath),
+			Entries:          entries,
+			FromSecretName:   parameters.RegistryAuthFromSecret,
+		}
+		secretName, err = build.ResolveSecret(&a.Client, a.Client.Namespace, a.ComponentName, registryAuth)
+		if err != nil {
+			return errors.Wrap(err, "unable to resolve registry credentials")
+		}
+		if secretName != "" {
+			// The secret is recreated per build rather than reused, so clean it up
+			// once the build finishes (or is interrupted) instead of leaking it -
+			// mirroring terminateBuild's cleanup of the BuildConfig path.
+			defer func() {
+				if derr := a.Client.DynamicClient.Resource(secretGroupVersionResource).Namespace(a.Client.Namespace).Delete(secretName, &metav1.DeleteOptions{}); derr != nil && err == nil {
+					err = errors.Wrap(derr, "unable to clean up registry credentials secret")
+				}
+			}()
+		}
+	}
+
+	buildParams := build.Parameters{
+		Client:             &a.Client,
+		ComponentName:      a.ComponentName,
+		BuildParameters:    parameters,
+		SecretName:         secretName,
+		ServiceAccountName: parameters.RegistryAuthServiceAccount,
+		Sync:               a.syncBuildContext(parameters),
+		Quiet:              parameters.Quiet,
+		Cache: build.Cache{
+			Enabled: !parameters.NoCache,
+			Repo:    parameters.CacheRepo,
+		},
+	}
+
+	// Cancel the build and let the strategy clean up its builder pod if
+	// the user hits ^C while the build is in progress.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	controlC := make(chan os.Signal, 1)
+	signal.Notify(controlC, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-controlC
+		cancel()
+	}()
+
+	log.Infof("\nBuilding component %s using the %s build strategy", a.ComponentName, strategy.Name())
+	err = strategy.Build(ctx, buildParams)
+	signal.Stop(controlC)
+	return err
+}
+
+// syncBuildContext returns a build.Parameters.Sync implementation that
+// streams the project source into the given container of the builder pod.
+// The strategy itself is responsible for signalling the builder pod once
+// Sync returns, so this only needs to get the bytes across.
+func (a Adapter) syncBuildContext(parameters common.BuildParameters) func(podName, containerName string) error {
+	return func(podName, containerName string) error {
+		log.Infof("\nSyncing to component %s", a.ComponentName)
+		syncAdapter := sync.New(a.AdapterContext, &a.Client)
+		compInfo := common.ComponentInfo{
+			ContainerName: containerName,
+			PodName:       podName,
+		}
+
+		syncFolder, err := syncAdapter.SyncFilesBuild(parameters, dockerfilePath)
+		if err != nil {
+			return err
+		}
+
+		if err := a.Client.ExtractProjectToComponent(compInfo, buildContextMountPath, syncFolder); err != nil {
+			return errors.Wrap(err, "failed to stream tarball into file transfer container")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FuncMap returns the curated subset of Sprig-style helpers a deploy
+// manifest template can use: default, required, toYaml, quote, indent and
+// nindent. The full Sprig library (200+ functions, including filesystem and
+// environment access) is deliberately not exposed to a manifest template -
+// `lookup` in particular is intentionally left out, since it would let a
+// manifest template read arbitrary cluster state at render time.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"default":  defaultFunc,
+		"required": requiredFunc,
+		"toYaml":   toYamlFunc,
+		"quote":    quoteFunc,
+		"indent":   indentFunc,
+		"nindent":  nindentFunc,
+	}
+}
+
+// defaultFunc returns value, or def if value is the zero value for its type.
+func defaultFunc(def, value interface{}) interface{} {
+	if isEmpty(value) {
+		return def
+	}
+	return value
+}
+
+// requiredFunc returns value, or fails the render with message if value is
+// the zero value for its type - used to produce a clear error for a
+// manifest's missing required value instead of silently rendering "<no value>".
+func requiredFunc(message string, value interface{}) (interface{}, error) {
+	if isEmpty(value) {
+		return nil, fmt.Errorf(message)
+	}
+	return value, nil
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	default:
+		return reflect.ValueOf(v).IsZero()
+	}
+}
+
+// toYamlFunc renders value as an inline YAML document, for embedding a
+// structured .Values entry (e.g. a map of annotations) into a manifest.
+func toYamlFunc(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func quoteFunc(value interface{}) string {
+	return fmt.Sprintf("%q", fmt.Sprintf("%v", value))
+}
+
+// indentFunc indents every line of value by spaces spaces.
+func indentFunc(spaces int, value string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nindentFunc is indentFunc with a leading newline, for embedding a
+// multi-line block (e.g. the output of toYaml) under a YAML key.
+func nindentFunc(spaces int, value string) string {
+	return "\n" + indentFunc(spaces, value)
+}
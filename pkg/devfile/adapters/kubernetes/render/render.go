@@ -0,0 +1,27 @@
+package render
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// Render executes manifestSource as a Go text/template against values
+// (exposed to the template as .Values), using FuncMap for helpers.
+//
+// Callers should render the whole manifest stream before splitting it on
+// "---": splitting first and rendering each document independently breaks
+// as soon as a template action (e.g. toYaml on a multi-document value, or a
+// conditional that spans documents) produces its own "---", since that
+// produced separator would be indistinguishable from a real one.
+func Render(manifestSource []byte, values Values) ([]byte, error) {
+	tmpl, err := template.New("deploy").Funcs(FuncMap()).Parse(string(manifestSource))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Values Values }{Values: values}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
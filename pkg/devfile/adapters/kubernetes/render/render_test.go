@@ -0,0 +1,195 @@
+package render
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := Values{
+		"image": "busybox",
+		"annotations": Values{
+			"a": "1",
+			"b": "2",
+		},
+	}
+	override := Values{
+		"image": "alpine",
+		"annotations": Values{
+			"b": "overridden",
+			"c": "3",
+		},
+		"replicas": 2,
+	}
+
+	got := Merge(base, override)
+
+	if got["image"] != "alpine" {
+		t.Errorf("image = %v, want alpine (later layer should win)", got["image"])
+	}
+	if got["replicas"] != 2 {
+		t.Errorf("replicas = %v, want 2", got["replicas"])
+	}
+
+	annotations, ok := got["annotations"].(Values)
+	if !ok {
+		t.Fatalf("annotations is %T, want Values", got["annotations"])
+	}
+	if annotations["a"] != "1" {
+		t.Errorf("annotations[a] = %v, want 1 (should survive from base layer)", annotations["a"])
+	}
+	if annotations["b"] != "overridden" {
+		t.Errorf("annotations[b] = %v, want overridden", annotations["b"])
+	}
+	if annotations["c"] != "3" {
+		t.Errorf("annotations[c] = %v, want 3", annotations["c"])
+	}
+}
+
+func TestMergeDoesNotMutateLayers(t *testing.T) {
+	base := Values{"key": "base"}
+	override := Values{"key": "override"}
+
+	Merge(base, override)
+
+	if base["key"] != "base" {
+		t.Errorf("base layer was mutated: base[key] = %v, want base", base["key"])
+	}
+}
+
+func TestParseSet(t *testing.T) {
+	got, err := ParseSet([]string{"image.repository=busybox", "replicas=3", "debug=true", "cpu=1.5"})
+	if err != nil {
+		t.Fatalf("ParseSet() error = %v", err)
+	}
+
+	image, ok := got["image"].(Values)
+	if !ok {
+		t.Fatalf("image is %T, want Values", got["image"])
+	}
+	if image["repository"] != "busybox" {
+		t.Errorf("image.repository = %v, want busybox", image["repository"])
+	}
+	if got["replicas"] != int64(3) {
+		t.Errorf("replicas = %v (%T), want int64(3)", got["replicas"], got["replicas"])
+	}
+	if got["debug"] != true {
+		t.Errorf("debug = %v, want true", got["debug"])
+	}
+	if got["cpu"] != 1.5 {
+		t.Errorf("cpu = %v, want 1.5", got["cpu"])
+	}
+}
+
+func TestParseSetInvalidAssignment(t *testing.T) {
+	if _, err := ParseSet([]string{"no-equals-sign"}); err == nil {
+		t.Fatal("ParseSet() error = nil, want an error for a value with no '='")
+	}
+}
+
+func TestParseSetFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(path, []byte("dummy-cert-contents"), 0644); err != nil {
+		t.Fatalf("unable to seed set-file fixture: %v", err)
+	}
+
+	got, err := ParseSetFile([]string{"tls.ca=@" + path})
+	if err != nil {
+		t.Fatalf("ParseSetFile() error = %v", err)
+	}
+
+	tls, ok := got["tls"].(Values)
+	if !ok {
+		t.Fatalf("tls is %T, want Values", got["tls"])
+	}
+	if tls["ca"] != "dummy-cert-contents" {
+		t.Errorf("tls.ca = %v, want dummy-cert-contents", tls["ca"])
+	}
+}
+
+func TestParseSetFileMissingFile(t *testing.T) {
+	if _, err := ParseSetFile([]string{"tls.ca=@" + filepath.Join(os.TempDir(), "does-not-exist")}); err == nil {
+		t.Fatal("ParseSetFile() error = nil, want an error for a missing file")
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{in: "true", want: true},
+		{in: "false", want: false},
+		{in: "42", want: int64(42)},
+		{in: "3.14", want: 3.14},
+		{in: "0", want: int64(0)},
+		{in: "1", want: int64(1)},
+		{in: "busybox", want: "busybox"},
+	}
+
+	for _, tt := range tests {
+		if got := coerce(tt.in); got != tt.want {
+			t.Errorf("coerce(%q) = %v (%T), want %v (%T)", tt.in, got, got, tt.want, tt.want)
+		}
+	}
+}
+
+func TestRenderConditional(t *testing.T) {
+	manifest := `replicas: {{ if .Values.debug }}1{{ else }}3{{ end }}`
+
+	got, err := Render([]byte(manifest), Values{"debug": true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.TrimSpace(string(got)) != "replicas: 1" {
+		t.Errorf("Render() = %q, want %q", got, "replicas: 1")
+	}
+
+	got, err = Render([]byte(manifest), Values{"debug": false})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.TrimSpace(string(got)) != "replicas: 3" {
+		t.Errorf("Render() = %q, want %q", got, "replicas: 3")
+	}
+}
+
+func TestRenderMissingRequiredValueProducesClearError(t *testing.T) {
+	manifest := `image: {{ required "spec.image is required" .Values.image }}`
+
+	_, err := Render([]byte(manifest), Values{})
+	if err == nil {
+		t.Fatal("Render() error = nil, want an error for a missing required value")
+	}
+	if !strings.Contains(err.Error(), "spec.image is required") {
+		t.Errorf("Render() error = %q, want it to contain the required() message", err)
+	}
+}
+
+func TestRenderPreservesYAMLAnchors(t *testing.T) {
+	manifest := `base: &base
+  image: {{ .Values.image }}
+override:
+  <<: *base
+  replicas: 3
+`
+
+	got, err := Render([]byte(manifest), Values{"image": "busybox"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `base: &base
+  image: busybox
+override:
+  <<: *base
+  replicas: 3
+`
+	if string(got) != want {
+		t.Errorf("Render() did not preserve the YAML anchor/alias untouched by templating:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+// Package render turns a devfile's raw deploy manifest template into a
+// concrete Kubernetes manifest stream, the way `helm template` renders a
+// chart: a merged value set is exposed to the template as .Values, and a
+// curated set of Sprig-style helpers (see funcs.go) make it possible to
+// express defaults, conditionals and ranges instead of odo's old fixed
+// CONTAINER_IMAGE/COMPONENT_NAME/PORT substitution.
+package render
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Values is the merged value set exposed to a manifest template as .Values.
+type Values map[string]interface{}
+
+// Merge layers values in order, each layer overriding keys already set by
+// an earlier one. Nested maps are merged key-by-key; every other value
+// type is replaced outright. This is how odo combines built-in values,
+// devfile-provided values, .odo/values.yaml, and --set/--set-file flags.
+func Merge(layers ...Values) Values {
+	merged := Values{}
+	for _, layer := range layers {
+		mergeInto(merged, layer)
+	}
+	return merged
+}
+
+func mergeInto(dst, src Values) {
+	for key, value := range src {
+		srcMap, isMap := asValues(value)
+		if !isMap {
+			dst[key] = value
+			continue
+		}
+		dstMap, ok := asValues(dst[key])
+		if !ok {
+			dstMap = Values{}
+		}
+		mergeInto(dstMap, srcMap)
+		dst[key] = dstMap
+	}
+}
+
+func asValues(value interface{}) (Values, bool) {
+	switch v := value.(type) {
+	case Values:
+		return v, true
+	case map[string]interface{}:
+		return Values(v), true
+	case map[interface{}]interface{}:
+		converted := Values{}
+		for k, val := range v {
+			if ks, ok := k.(string); ok {
+				converted[ks] = val
+			}
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// LoadValuesFile reads and parses a Helm-style values.yaml file. A missing
+// file is not an error; it yields an empty Values.
+func LoadValuesFile(path string) (Values, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Values{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid values file %s: %w", path, err)
+	}
+	if raw == nil {
+		return Values{}, nil
+	}
+	return Values(raw), nil
+}
+
+// ParseSet parses Helm-style "a.b.c=value" assignments, as produced by
+// repeated `--set` flags, into a nested Values tree. Values that look like
+// a bool or a number are coerced, matching Helm's own --set behavior.
+func ParseSet(assignments []string) (Values, error) {
+	result := Values{}
+	for _, assignment := range assignments {
+		key, value, ok := splitAssignment(assignment)
+		if !ok {
+			return nil, fmt.Errorf("invalid --set value %q, expected key=value", assignment)
+		}
+		if err := setPath(result, strings.Split(key, "."), coerce(value)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ParseSetFile parses "a.b.c=@path" assignments, as produced by repeated
+// `--set-file` flags, into a nested Values tree, with each value read
+// verbatim from the referenced file.
+func ParseSetFile(assignments []string) (Values, error) {
+	result := Values{}
+	for _, assignment := range assignments {
+		key, value, ok := splitAssignment(assignment)
+		if !ok {
+			return nil, fmt.Errorf("invalid --set-file value %q, expected key=@path", assignment)
+		}
+		path := strings.TrimPrefix(value, "@")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read --set-file value for %s: %w", key, err)
+		}
+		if err := setPath(result, strings.Split(key, "."), string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func splitAssignment(assignment string) (key, value string, ok bool) {
+	idx := strings.Index(assignment, "=")
+	if idx < 0 {
+		return "", "", false
+	}
+	return assignment[:idx], assignment[idx+1:], true
+}
+
+func setPath(values Values, path []string, value interface{}) error {
+	if len(path) == 0 || path[0] == "" {
+		return fmt.Errorf("empty key in --set/--set-file assignment")
+	}
+	if len(path) == 1 {
+		values[path[0]] = value
+		return nil
+	}
+	next, ok := asValues(values[path[0]])
+	if !ok {
+		next = Values{}
+	}
+	if err := setPath(next, path[1:], value); err != nil {
+		return err
+	}
+	values[path[0]] = next
+	return nil
+}
+
+// coerce converts a --set value into a bool/int64/float64 when it looks
+// like one, falling back to a plain string. Int/float are checked before
+// bool because strconv.ParseBool accepts "0"/"1" as valid booleans, which
+// would otherwise silently turn replicas=1 into the bool true instead of
+// int64(1).
+func coerce(value string) interface{} {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return value
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+
+	"github.com/openshift/odo/pkg/auth"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DockerConfigJSONKey is the data key a `kubernetes.io/dockerconfigjson`
+// Secret stores its payload under.
+const DockerConfigJSONKey = corev1.DockerConfigJsonKey
+
+// dockerConfigJSON is the `kubernetes.io/dockerconfigjson` Secret payload
+// shape: a single "auths" map keyed by registry host.
+type dockerConfigJSON struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// CreateDockerConfigDataFromFilepath reads a docker/podman config.json-style
+// credential file at path and returns it as Secret data keyed by
+// DockerConfigJSONKey. Every registry the file mentions under a static
+// "auths" entry or a per-registry "credHelpers" entry is resolved through
+// auth.Config.ResolveAuth, so a Secret built from this data is
+// self-contained and keeps working once it's copied into a cluster where
+// the credential helper binary itself isn't installed. A registry whose
+// helper isn't on $PATH is skipped (ResolveAuth warns and returns ok=false)
+// rather than failing the whole read.
+//
+// "credsStore" is a single config-wide fallback helper, not keyed by
+// registry, so it names no registry this function can discover on its own -
+// a config.json of the common shape {"credsStore": "desktop"} with no
+// "auths"/"credHelpers" entries yields a Secret with an empty "auths" map.
+// Callers that know the registry in advance (e.g. --registry-auth
+// registry=path) must resolve it themselves via auth.Load + ResolveAuth
+// instead of going through this discovery path.
+func CreateDockerConfigDataFromFilepath(path string) (map[string][]byte, error) {
+	config, err := auth.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	registries := map[string]bool{}
+	for registry := range config.Auths {
+		registries[registry] = true
+	}
+	for registry := range config.CredHelpers {
+		registries[registry] = true
+	}
+
+	merged := dockerConfigJSON{Auths: map[string]json.RawMessage{}}
+	for registry := range registries {
+		entry, ok, err := config.ResolveAuth(registry)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			merged.Auths[registry] = entry
+		}
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{DockerConfigJSONKey: data}, nil
+}
+
+// CreateSecret builds (but does not create in the cluster) a
+// `kubernetes.io/dockerconfigjson` Secret object named name in namespace
+// with the given data.
+func CreateSecret(name, namespace string, data map[string][]byte) (*unstructured.Unstructured, error) {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: data,
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
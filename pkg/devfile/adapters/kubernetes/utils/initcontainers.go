@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/odo/pkg/devfile/adapters/common"
+	"github.com/openshift/odo/pkg/devfile/parser"
+	versionsCommon "github.com/openshift/odo/pkg/devfile/parser/data/common"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var invalidDNS1123Chars = regexp.MustCompile(`[^-a-z0-9]+`)
+
+// GenerateInitContainers walks devfileObj's events.preStart list and returns
+// one corev1.Container per referenced exec command, in execution order, for
+// use as podSpec.InitContainers. This lets PreStart commands (schema
+// migrations, permission fix-ups, rendering templates into the shared source
+// volume) run to completion before the main containers start, instead of
+// racing them the way a PostStart exec in the running pod does.
+//
+// A composite PreStart command with Parallel: true is rejected, since
+// Kubernetes always runs InitContainers strictly in sequence - there is no
+// way to honor a request for parallel execution there.
+func GenerateInitContainers(devfileObj parser.DevfileObj, containers []corev1.Container) ([]corev1.Container, error) {
+	preStartEvents := devfileObj.Data.GetEvents().PreStart
+	if len(preStartEvents) == 0 {
+		return nil, nil
+	}
+
+	commandMap := common.GetCommandsMap(devfileObj.Data.GetCommands())
+
+	var initContainers []corev1.Container
+	for _, commandName := range preStartEvents {
+		command, ok := commandMap[strings.ToLower(commandName)]
+		if !ok {
+			return nil, fmt.Errorf("unable to find devfile command %q referenced by a preStart event", commandName)
+		}
+
+		resolved, err := initContainersForCommand(command, commandMap, containers)
+		if err != nil {
+			return nil, err
+		}
+		initContainers = append(initContainers, resolved...)
+	}
+
+	return initContainers, nil
+}
+
+// initContainersForCommand flattens a single preStart command - exec or
+// composite - into one or more ordered init containers.
+func initContainersForCommand(command versionsCommon.DevfileCommand, commandMap map[string]versionsCommon.DevfileCommand, containers []corev1.Container) ([]corev1.Container, error) {
+	if command.Composite != nil {
+		if command.Composite.Parallel {
+			return nil, fmt.Errorf("preStart command %q is a parallel composite command, which is not supported since Kubernetes runs init containers strictly in sequence", command.Composite.Id)
+		}
+
+		var result []corev1.Container
+		for _, childName := range command.Composite.Commands {
+			child, ok := commandMap[strings.ToLower(childName)]
+			if !ok {
+				return nil, fmt.Errorf("unable to find devfile command %q referenced by composite command %q", childName, command.Composite.Id)
+			}
+			childContainers, err := initContainersForCommand(child, commandMap, containers)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, childContainers...)
+		}
+		return result, nil
+	}
+
+	initContainer, err := initContainerForExec(*command.Exec, containers)
+	if err != nil {
+		return nil, err
+	}
+	return []corev1.Container{initContainer}, nil
+}
+
+// initContainerForExec builds the init container for a single exec command,
+// taking WorkingDir from the exec command and copying Image/Env/VolumeMounts
+// from the devfile component it references, so the init container sees the same shared source volume,
+// mounted read-write, as the main container it is preparing for.
+func initContainerForExec(execCmd versionsCommon.Exec, containers []corev1.Container) (corev1.Container, error) {
+	source, err := initContainerSourceComponent(containers, execCmd.Component)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+
+	return corev1.Container{
+		Name:         initContainerName(execCmd.Id),
+		Image:        source.Image,
+		WorkingDir:   execCmd.WorkingDir,
+		Command:      []string{"/bin/sh", "-c"},
+		Args:         []string{execCmd.CommandLine},
+		Env:          source.Env,
+		VolumeMounts: source.VolumeMounts,
+	}, nil
+}
+
+func initContainerSourceComponent(containers []corev1.Container, name string) (*corev1.Container, error) {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find devfile component %q referenced by a preStart command", name)
+}
+
+// initContainerName derives an RFC-1123 compliant container name from a
+// devfile command id: lowercased, with any run of characters outside
+// [-a-z0-9] collapsed to a single "-", prefixed with "init-" to keep it
+// distinct from the component's main containers.
+//
+// If sanitizing strips every character (e.g. a command id of "@@@"), the
+// raw command id can't be reused verbatim - it's exactly the invalid input
+// that got us here - so fall back to a short hash of it instead, keeping
+// the name both RFC-1123 compliant and derived from the command id.
+func initContainerName(commandId string) string {
+	name := invalidDNS1123Chars.ReplaceAllString(strings.ToLower(commandId), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = fmt.Sprintf("init-cmd-%x", fnvHash(commandId))
+	} else {
+		name = "init-" + name
+	}
+
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength], "-")
+	}
+	return name
+}
+
+func fnvHash(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestInitContainerName(t *testing.T) {
+	tests := []struct {
+		name      string
+		commandId string
+	}{
+		{name: "simple id", commandId: "build"},
+		{name: "mixed case and spaces", commandId: "Run Migrations"},
+		{name: "id that sanitizes to empty", commandId: "@@@"},
+		{name: "id over the DNS1123 label length limit", commandId: "a-very-long-command-id-that-goes-on-and-on-and-on-and-on-and-on-and-on-and-on-and-on"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := initContainerName(tt.commandId)
+			if errs := validation.IsDNS1123Label(got); len(errs) != 0 {
+				t.Fatalf("initContainerName(%q) = %q, not RFC-1123 compliant: %v", tt.commandId, got, errs)
+			}
+		})
+	}
+}
+
+func TestInitContainerNameEmptyAfterSanitizingIsDeterministic(t *testing.T) {
+	first := initContainerName("@@@")
+	second := initContainerName("@@@")
+	if first != second {
+		t.Fatalf("initContainerName(%q) is not deterministic: %q != %q", "@@@", first, second)
+	}
+
+	other := initContainerName("###")
+	if first == other {
+		t.Fatalf("initContainerName produced the same fallback name for different command ids %q and %q", "@@@", "###")
+	}
+}
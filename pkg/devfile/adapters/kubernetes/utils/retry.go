@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// RetryOptions bounds how long/how many times the retry helpers in this
+// file retry a dynamic Create/Update/Delete before giving up. Exposed so
+// tests can drive the retry loop deterministically.
+type RetryOptions struct {
+	MaxAttempts int
+	MaxElapsed  time.Duration
+}
+
+// DefaultRetryOptions is used by callers that don't need a tighter bound.
+var DefaultRetryOptions = RetryOptions{MaxAttempts: 8, MaxElapsed: 30 * time.Second}
+
+const (
+	minRetryBackoff = 200 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
+)
+
+// MutateFunc reconciles desired with the freshly-fetched live object
+// (e.g. carrying over live annotations or a Service's clusterIP into
+// desired) whenever CreateOrUpdate falls through to an Update; desired is
+// what is then sent as the Update.
+type MutateFunc func(live, desired *unstructured.Unstructured)
+
+// Retry calls fn until it succeeds, a non-retryable error is returned, or
+// opts is exhausted, backing off exponentially with jitter between
+// attempts. fn is responsible for re-reading any state it needs fresh on
+// each attempt (e.g. re-Get before re-Update).
+func Retry(opts RetryOptions, fn func(attempt int) error) error {
+	deadline := time.Now().Add(opts.MaxElapsed)
+	backoff := minRetryBackoff
+	var lastErr error
+
+	for attempt := 0; attempt < opts.MaxAttempts && time.Now().Before(deadline); attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		lastErr = err
+
+		wait := backoff
+		if after, ok := retryAfter(err); ok {
+			wait = after
+		}
+		time.Sleep(jitterDuration(wait))
+		backoff = nextBackoff(backoff)
+	}
+
+	return fmt.Errorf("giving up after retries: %w", lastErr)
+}
+
+// CreateOrUpdate creates desired if it doesn't exist yet, or updates it if
+// it does, retrying the transient errors a concurrent `odo deploy` or a
+// mutating controller can produce:
+//   - IsConflict: re-Get the live object, let mutate reconcile desired
+//     with it, copy its resourceVersion onto desired, and retry the Update.
+//   - IsAlreadyExists from Create: fall through to the Update path.
+//   - IsNotFound during Update: fall back to Create.
+//   - IsServerTimeout/IsTooManyRequests/IsServiceUnavailable: retry the
+//     same operation verbatim, honoring Retry-After when present.
+//
+// Non-retryable errors (IsForbidden, IsInvalid, IsBadRequest, ...) are
+// returned immediately.
+func CreateOrUpdate(resource dynamic.ResourceInterface, desired *unstructured.Unstructured, mutate MutateFunc, opts RetryOptions) (*unstructured.Unstructured, error) {
+	var result *unstructured.Unstructured
+
+	err := Retry(opts, func(attempt int) error {
+		created, err := resource.Create(desired, metav1.CreateOptions{})
+		if err == nil {
+			result = created
+			return nil
+		}
+		if !kerrors.IsAlreadyExists(err) && !kerrors.IsConflict(err) {
+			return err
+		}
+
+		live, err := resource.Get(desired.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			// The object disappeared between Create and Get; retrying
+			// Create on the next attempt is the right move.
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if mutate != nil {
+			mutate(live, desired)
+		}
+		desired.SetResourceVersion(live.GetResourceVersion())
+
+		updated, err := resource.Update(desired, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		result = updated
+		return nil
+	})
+
+	return result, err
+}
+
+// DeleteWithRetry deletes name via resource, retrying transient errors and
+// treating IsNotFound as success since the desired end state is reached.
+func DeleteWithRetry(resource dynamic.ResourceInterface, name string, opts RetryOptions) error {
+	return Retry(opts, func(attempt int) error {
+		err := resource.Delete(name, &metav1.DeleteOptions{})
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// isRetryable reports whether err is one of the transient API errors this
+// package retries; any other error (including nil) is treated as final.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return kerrors.IsServerTimeout(err) || kerrors.IsTooManyRequests(err) ||
+		kerrors.IsServiceUnavailable(err) || kerrors.IsConflict(err) || kerrors.IsNotFound(err)
+}
+
+// retryAfter extracts the server-suggested retry delay from err's status
+// details, if any.
+func retryAfter(err error) (time.Duration, bool) {
+	status, ok := err.(kerrors.APIStatus)
+	if !ok {
+		return 0, false
+	}
+	details := status.Status().Details
+	if details == nil || details.RetryAfterSeconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(details.RetryAfterSeconds) * time.Second, true
+}
+
+// nextBackoff doubles d, capped at maxRetryBackoff.
+func nextBackoff(d time.Duration) time.Duration {
+	d *= 2
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return d
+}
+
+// jitterDuration returns d plus up to 25% random jitter so concurrent
+// callers don't retry in lockstep.
+func jitterDuration(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var (
+	testGroupResource = schema.GroupResource{Group: "", Resource: "foos"}
+	testGroupKind     = schema.GroupKind{Group: "", Kind: "Foo"}
+)
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want time.Duration
+	}{
+		{in: 200 * time.Millisecond, want: 400 * time.Millisecond},
+		{in: 2500 * time.Millisecond, want: 5 * time.Second},
+		{in: 5 * time.Second, want: 5 * time.Second},
+		{in: 10 * time.Second, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := nextBackoff(tt.in); got != tt.want {
+			t.Errorf("nextBackoff(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJitterDuration(t *testing.T) {
+	d := 200 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := jitterDuration(d)
+		if got < d {
+			t.Fatalf("jitterDuration(%s) = %s, want >= %s", d, got, d)
+		}
+		if got > d+d/4+1 {
+			t.Fatalf("jitterDuration(%s) = %s, want <= %s", d, got, d+d/4+1)
+		}
+	}
+}
+
+func TestJitterDurationZero(t *testing.T) {
+	if got := jitterDuration(0); got < 0 {
+		t.Fatalf("jitterDuration(0) = %s, want >= 0", got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "conflict", err: kerrors.NewConflict(testGroupResource, "foo", errors.New("conflict")), want: true},
+		{name: "server timeout", err: kerrors.NewServerTimeout(testGroupResource, "get", 1), want: true},
+		{name: "too many requests", err: kerrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "service unavailable", err: kerrors.NewServiceUnavailable("down"), want: true},
+		{name: "not found", err: kerrors.NewNotFound(testGroupResource, "foo"), want: true},
+		{name: "forbidden", err: kerrors.NewForbidden(testGroupResource, "foo", errors.New("forbidden")), want: false},
+		{name: "invalid", err: kerrors.NewInvalid(testGroupKind, "foo", nil), want: false},
+		{name: "plain error", err: fmt.Errorf("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	withRetryAfter := &kerrors.StatusError{ErrStatus: metav1.Status{
+		Details: &metav1.StatusDetails{RetryAfterSeconds: 7},
+	}}
+	if d, ok := retryAfter(withRetryAfter); !ok || d != 7*time.Second {
+		t.Fatalf("retryAfter(withRetryAfter) = (%s, %v), want (7s, true)", d, ok)
+	}
+
+	withoutRetryAfter := &kerrors.StatusError{ErrStatus: metav1.Status{}}
+	if _, ok := retryAfter(withoutRetryAfter); ok {
+		t.Fatalf("retryAfter(withoutRetryAfter) reported a retry-after, want none")
+	}
+
+	if _, ok := retryAfter(fmt.Errorf("boom")); ok {
+		t.Fatalf("retryAfter(non-status error) reported a retry-after, want none")
+	}
+}
+
+func TestRetrySucceedsAfterTransientErrors(t *testing.T) {
+	attempts := 0
+	opts := RetryOptions{MaxAttempts: 5, MaxElapsed: time.Second}
+
+	err := Retry(opts, func(attempt int) error {
+		attempts++
+		if attempts < 3 {
+			return kerrors.NewConflict(testGroupResource, "foo", errors.New("conflict"))
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Retry() = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("Retry() called fn %d times, want 3", attempts)
+	}
+}
+
+func TestRetryStopsOnNonRetryableError(t *testing.T) {
+	attempts := 0
+	opts := RetryOptions{MaxAttempts: 5, MaxElapsed: time.Second}
+	wantErr := fmt.Errorf("not retryable")
+
+	err := Retry(opts, func(attempt int) error {
+		attempts++
+		return wantErr
+	})
+
+	if err != wantErr {
+		t.Fatalf("Retry() = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Fatalf("Retry() called fn %d times, want 1", attempts)
+	}
+}
+
+var testSecretsGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}
+
+const testSecretsNamespace = "test-namespace"
+
+// newTestSecret returns a bare-bones unstructured Secret, the same shape
+// CreateOrUpdate/DeleteWithRetry's real callers (ResolveSecret, CopySecret)
+// hand it.
+func newTestSecret(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testSecretsNamespace,
+		},
+	}}
+}
+
+// newFakeSecretsClient returns a fake dynamic client seeded with objects,
+// scoped to the secrets resource/namespace CreateOrUpdate/DeleteWithRetry
+// are tested against, alongside the underlying fake client so tests can
+// install reactors to simulate conflicts.
+func newFakeSecretsClient(objects ...runtime.Object) (dynamic.ResourceInterface, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	gvrToListKind := map[schema.GroupVersionResource]string{testSecretsGVR: "SecretList"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind, objects...)
+	return client.Resource(testSecretsGVR).Namespace(testSecretsNamespace), client
+}
+
+func TestCreateOrUpdateCreatesWhenAbsent(t *testing.T) {
+	resource, _ := newFakeSecretsClient()
+	desired := newTestSecret("regcred")
+
+	got, err := CreateOrUpdate(resource, desired, nil, RetryOptions{MaxAttempts: 1, MaxElapsed: time.Second})
+	if err != nil {
+		t.Fatalf("CreateOrUpdate() error = %v", err)
+	}
+	if got.GetName() != "regcred" {
+		t.Fatalf("CreateOrUpdate() created %q, want regcred", got.GetName())
+	}
+
+	live, err := resource.Get("regcred", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() after CreateOrUpdate() error = %v", err)
+	}
+	if live.GetName() != "regcred" {
+		t.Fatalf("Get() returned %q, want regcred", live.GetName())
+	}
+}
+
+func TestCreateOrUpdateUpdatesWhenAlreadyExists(t *testing.T) {
+	existing := newTestSecret("regcred")
+	existing.SetLabels(map[string]string{"phase": "before"})
+	resource, _ := newFakeSecretsClient(existing)
+
+	desired := newTestSecret("regcred")
+	desired.SetLabels(map[string]string{"phase": "after"})
+
+	got, err := CreateOrUpdate(resource, desired, nil, RetryOptions{MaxAttempts: 1, MaxElapsed: time.Second})
+	if err != nil {
+		t.Fatalf("CreateOrUpdate() error = %v", err)
+	}
+	if got.GetLabels()["phase"] != "after" {
+		t.Fatalf("CreateOrUpdate() labels = %v, want phase=after", got.GetLabels())
+	}
+}
+
+func TestCreateOrUpdateRetriesConflictWithMutate(t *testing.T) {
+	existing := newTestSecret("regcred")
+	existing.SetLabels(map[string]string{"phase": "before"})
+	resource, client := newFakeSecretsClient(existing)
+
+	updateAttempts := 0
+	client.PrependReactor("update", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		updateAttempts++
+		if updateAttempts == 1 {
+			return true, nil, kerrors.NewConflict(testGroupResource, "regcred", errors.New("conflict"))
+		}
+		return false, nil, nil
+	})
+
+	desired := newTestSecret("regcred")
+	mutateCalls := 0
+	mutate := func(live, desired *unstructured.Unstructured) {
+		mutateCalls++
+		desired.SetLabels(map[string]string{"phase": "mutated", "seen-from-live": live.GetLabels()["phase"]})
+	}
+
+	got, err := CreateOrUpdate(resource, desired, mutate, RetryOptions{MaxAttempts: 5, MaxElapsed: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("CreateOrUpdate() error = %v", err)
+	}
+	if updateAttempts < 2 {
+		t.Fatalf("CreateOrUpdate() attempted Update %d time(s), want at least 2 (a retry after the conflict)", updateAttempts)
+	}
+	if mutateCalls < 2 {
+		t.Fatalf("CreateOrUpdate() called mutate %d time(s), want at least 2", mutateCalls)
+	}
+	if got.GetLabels()["phase"] != "mutated" {
+		t.Fatalf("CreateOrUpdate() labels = %v, want phase=mutated", got.GetLabels())
+	}
+	if got.GetLabels()["seen-from-live"] != "before" {
+		t.Fatalf("CreateOrUpdate() mutate saw live labels = %v, want seen-from-live=before", got.GetLabels())
+	}
+}
+
+func TestDeleteWithRetryDeletesExisting(t *testing.T) {
+	resource, _ := newFakeSecretsClient(newTestSecret("regcred"))
+
+	if err := DeleteWithRetry(resource, "regcred", RetryOptions{MaxAttempts: 1, MaxElapsed: time.Second}); err != nil {
+		t.Fatalf("DeleteWithRetry() error = %v", err)
+	}
+
+	if _, err := resource.Get("regcred", metav1.GetOptions{}); !kerrors.IsNotFound(err) {
+		t.Fatalf("Get() after DeleteWithRetry() error = %v, want IsNotFound", err)
+	}
+}
+
+func TestDeleteWithRetryTreatsNotFoundAsSuccess(t *testing.T) {
+	resource, _ := newFakeSecretsClient()
+
+	if err := DeleteWithRetry(resource, "does-not-exist", RetryOptions{MaxAttempts: 1, MaxElapsed: time.Second}); err != nil {
+		t.Fatalf("DeleteWithRetry() error = %v, want nil for an already-absent object", err)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	opts := RetryOptions{MaxAttempts: 3, MaxElapsed: time.Minute}
+
+	err := Retry(opts, func(attempt int) error {
+		attempts++
+		return kerrors.NewConflict(testGroupResource, "foo", errors.New("conflict"))
+	})
+
+	if err == nil {
+		t.Fatal("Retry() = nil, want an error after exhausting MaxAttempts")
+	}
+	if attempts != opts.MaxAttempts {
+		t.Fatalf("Retry() called fn %d times, want %d", attempts, opts.MaxAttempts)
+	}
+}
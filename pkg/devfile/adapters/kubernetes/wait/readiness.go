@@ -0,0 +1,131 @@
+package wait
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// isReady dispatches to a per-kind readiness check using t's typed status,
+// mirroring the semantics `helm install --wait` uses for each kind. Kinds
+// with no specific check are considered ready as soon as they exist.
+func (w *Waiter) isReady(t Target) (bool, string, error) {
+	obj, err := w.get(t)
+	if err != nil {
+		return false, "", err
+	}
+	if obj == nil {
+		return false, "not created yet", nil
+	}
+
+	switch t.Kind {
+	case "Deployment", "StatefulSet":
+		return readyRollout(obj)
+	case "DaemonSet":
+		return readyDaemonSet(obj)
+	case "PersistentVolumeClaim":
+		return readyPVC(obj)
+	case "Service":
+		return w.readyService(t, obj)
+	case "Pod":
+		return readyPod(obj)
+	case "Job":
+		return readyJob(obj)
+	default:
+		return true, "exists", nil
+	}
+}
+
+// readyRollout implements the Deployment/StatefulSet readiness check:
+// status.observedGeneration must have caught up with metadata.generation
+// and status.availableReplicas must meet spec.replicas.
+func readyRollout(obj *unstructured.Unstructured) (bool, string, error) {
+	generation := obj.GetGeneration()
+	observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if observedGeneration < generation {
+		return false, fmt.Sprintf("waiting for rollout to be observed (generation %d, observed %d)", generation, observedGeneration), nil
+	}
+
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	availableReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	if availableReplicas < replicas {
+		return false, fmt.Sprintf("%d/%d replicas available", availableReplicas, replicas), nil
+	}
+
+	return true, "rollout complete", nil
+}
+
+// readyDaemonSet requires every desired pod to be available.
+func readyDaemonSet(obj *unstructured.Unstructured) (bool, string, error) {
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberAvailable")
+	if available < desired {
+		return false, fmt.Sprintf("%d/%d pods available", available, desired), nil
+	}
+	return true, "rollout complete", nil
+}
+
+// readyPVC requires the claim to have been bound to a volume.
+func readyPVC(obj *unstructured.Unstructured) (bool, string, error) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase != "Bound" {
+		return false, fmt.Sprintf("phase is %q", phase), nil
+	}
+	return true, "bound", nil
+}
+
+// readyPod requires the Ready condition to be True.
+func readyPod(obj *unstructured.Unstructured) (bool, string, error) {
+	return conditionTrue(obj, "Ready", "pod not ready")
+}
+
+// readyJob requires the Complete condition to be True.
+func readyJob(obj *unstructured.Unstructured) (bool, string, error) {
+	return conditionTrue(obj, "Complete", "job not complete")
+}
+
+// readyService requires a ClusterIP/NodePort service to have a matching
+// Endpoints object with at least one non-empty subset, or a LoadBalancer
+// service to have at least one ingress assigned.
+func (w *Waiter) readyService(t Target, obj *unstructured.Unstructured) (bool, string, error) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+
+	if serviceType == "LoadBalancer" {
+		ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+		if len(ingress) == 0 {
+			return false, "waiting for load balancer ingress to be assigned", nil
+		}
+		return true, "load balancer ready", nil
+	}
+
+	endpoints, err := w.Client.KubeClient.CoreV1().Endpoints(t.Namespace).Get(t.Name, metav1.GetOptions{})
+	if err != nil {
+		return false, "waiting for endpoints", nil
+	}
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, "endpoints ready", nil
+		}
+	}
+	return false, "no endpoints have a ready address yet", nil
+}
+
+// conditionTrue reports whether obj's status.conditions contains a
+// condition of the given type with status "True".
+func conditionTrue(obj *unstructured.Unstructured, conditionType, notReadyMessage string) (bool, string, error) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == "True" {
+			return true, fmt.Sprintf("%s=True", conditionType), nil
+		}
+	}
+	return false, notReadyMessage, nil
+}
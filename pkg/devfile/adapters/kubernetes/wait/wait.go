@@ -0,0 +1,117 @@
+// Package wait polls the objects `odo deploy` applies to the cluster until
+// they report themselves ready, in the spirit of Helm's `--wait`. Readiness
+// is determined per object kind (see readiness.go) since a generic "exists"
+// check hides real rollout failures behind a fixed sleep.
+package wait
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/openshift/odo/pkg/kclient"
+	"github.com/openshift/odo/pkg/log"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultTimeout is used when the caller does not set a --wait-timeout.
+const DefaultTimeout = 2 * time.Minute
+
+const (
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+// Target is a single applied object the Waiter should poll for readiness.
+type Target struct {
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Name      string
+	Kind      string
+}
+
+// Waiter polls a set of applied objects until each reports ready per its
+// kind's readiness semantics, or a timeout elapses.
+type Waiter struct {
+	Client *kclient.Client
+}
+
+// New returns a Waiter that polls using client's dynamic client.
+func New(client *kclient.Client) *Waiter {
+	return &Waiter{Client: client}
+}
+
+// Wait blocks until every target is ready, ctx is cancelled, or timeout
+// elapses, whichever happens first. On timeout it returns an error naming
+// the last target found not ready on the final poll along with the last status/message
+// observed for it, so users see *why* instead of a generic timeout.
+func (w *Waiter) Wait(ctx context.Context, targets []Target, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	s := log.Spinnerf("Waiting for %d resource(s) to be ready", len(targets))
+	defer s.End(false)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := minBackoff
+	var lastErr error
+	for {
+		allReady := true
+		for _, t := range targets {
+			ready, message, err := w.isReady(t)
+			if err != nil {
+				return errors.Wrapf(err, "error while checking readiness of %s %q", t.Kind, t.Name)
+			}
+			if !ready {
+				allReady = false
+				lastErr = fmt.Errorf("%s %q is not ready yet: %s", t.Kind, t.Name, message)
+			}
+		}
+
+		if allReady {
+			s.End(true)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return errors.Wrap(lastErr, "timed out waiting for resources to become ready")
+			}
+			return ctx.Err()
+		case <-time.After(jitter(backoff)):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// jitter returns d plus up to 25% random jitter, so many targets don't all
+// poll the API server in lockstep.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration(rand.Int63n(int64(d)/4+1))
+}
+
+// get fetches the live object for t, returning (nil, nil) if it has not
+// appeared yet.
+func (w *Waiter) get(t Target) (*unstructured.Unstructured, error) {
+	obj, err := w.Client.DynamicClient.Resource(t.GVR).Namespace(t.Namespace).Get(t.Name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,48 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/odo/pkg/util"
+)
+
+// SandboxKubeconfig creates a node-local copy of $KUBECONFIG inside a new
+// temporary directory and returns that directory (not the kubeconfig file
+// itself - it's kept at "kubeconfig" under it, the same shape
+// CreateNewContext already returns), so it composes with the existing
+// DeleteDir(dir) cleanup pattern.
+//
+// node distinguishes the directory per parallel Ginkgo node (pass
+// ginkgo.GinkgoParallelProcess()), so that two nodes running `odo
+// login`/`odo logout` concurrently never share - and corrupt - each
+// other's current-context, tokens or users the way a single suite-wide
+// tempdir would under `ginkgo -p`.
+//
+// If $KUBECONFIG isn't set, SandboxKubeconfig is a no-op: it returns ""
+// and a nil error.
+func SandboxKubeconfig(node int) (string, error) {
+	original := os.Getenv("KUBECONFIG")
+	if original == "" {
+		return "", nil
+	}
+
+	info, err := os.Stat(original)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir("", fmt.Sprintf("odo-kubeconfig-node%d-", node))
+	if err != nil {
+		return "", err
+	}
+
+	if err := util.CopyFile(original, filepath.Join(dir, "kubeconfig"), info); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
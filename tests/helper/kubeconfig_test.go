@@ -0,0 +1,90 @@
+package helper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestSandboxKubeconfigContention simulates multiple parallel Ginkgo nodes
+// sandboxing $KUBECONFIG and then mutating their own copy concurrently -
+// standing in for concurrent `odo login`/`odo logout` calls, which this
+// tree has no cluster to actually run against - and asserts that no node
+// ever observes another node's context.
+func TestSandboxKubeconfigContention(t *testing.T) {
+	original, err := ioutil.TempFile("", "odo-kubeconfig-contention-")
+	if err != nil {
+		t.Fatalf("unable to create source kubeconfig: %v", err)
+	}
+	defer os.Remove(original.Name())
+	if _, err := original.WriteString("current-context: original\n"); err != nil {
+		t.Fatalf("unable to seed source kubeconfig: %v", err)
+	}
+	original.Close()
+
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+	os.Setenv("KUBECONFIG", original.Name())
+
+	const nodeCount = 5
+	dirs := make([]string, nodeCount)
+	errs := make([]error, nodeCount)
+
+	var wg sync.WaitGroup
+	for node := 0; node < nodeCount; node++ {
+		wg.Add(1)
+		go func(node int) {
+			defer wg.Done()
+
+			dir, err := SandboxKubeconfig(node)
+			if err != nil {
+				errs[node] = err
+				return
+			}
+			dirs[node] = dir
+
+			// Stand in for `odo login`/`odo logout` rewriting current-context.
+			path := filepath.Join(dir, "kubeconfig")
+			errs[node] = ioutil.WriteFile(path, []byte(fmt.Sprintf("current-context: node%d\n", node)), 0644)
+		}(node)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for node := 0; node < nodeCount; node++ {
+		if errs[node] != nil {
+			t.Fatalf("node %d: %v", node, errs[node])
+		}
+		if seen[dirs[node]] {
+			t.Fatalf("node %d reused a sandbox directory already assigned to another node: %s", node, dirs[node])
+		}
+		seen[dirs[node]] = true
+		defer os.RemoveAll(dirs[node])
+
+		content, err := ioutil.ReadFile(filepath.Join(dirs[node], "kubeconfig"))
+		if err != nil {
+			t.Fatalf("node %d: unable to read its sandboxed kubeconfig: %v", node, err)
+		}
+		want := fmt.Sprintf("current-context: node%d\n", node)
+		if string(content) != want {
+			t.Fatalf("node %d: sandboxed kubeconfig was contaminated: got %q, want %q", node, content, want)
+		}
+	}
+}
+
+func TestSandboxKubeconfigNoop(t *testing.T) {
+	oldKubeconfig := os.Getenv("KUBECONFIG")
+	defer os.Setenv("KUBECONFIG", oldKubeconfig)
+	os.Unsetenv("KUBECONFIG")
+
+	dir, err := SandboxKubeconfig(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dir != "" {
+		t.Fatalf("expected no sandbox directory when $KUBECONFIG is unset, got %q", dir)
+	}
+}
@@ -0,0 +1,31 @@
+// Command gen-suites scans tests/integration and tests/e2e for Ginkgo spec
+// packages missing their Test* bootstrap and writes a canonical
+// suite_test.go for each. It backs the `make gen-suites` target.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/odo/tests/helper/suitegen"
+)
+
+var roots = []string{"tests/integration", "tests/e2e"}
+
+func main() {
+	for _, root := range roots {
+		suites, err := suitegen.Discover(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "gen-suites: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, s := range suites {
+			if err := suitegen.Write(s); err != nil {
+				fmt.Fprintf(os.Stderr, "gen-suites: %s: %v\n", s.Dir, err)
+				os.Exit(1)
+			}
+			fmt.Printf("gen-suites: wrote %s/suite_test.go (%s Suite)\n", s.Dir, s.Name)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package suitegen scans a directory tree for Ginkgo spec packages that are
+// missing their Test* bootstrap and emits a canonical suite_test.go for
+// each one, mirroring the shape of loginlogout's TestLoginlogout file. It
+// backs the `make gen-suites` target.
+package suitegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Suite describes one tests/integration or tests/e2e package directory that
+// has Describe(...) specs but no Test* bootstrap wiring it into `go test`.
+type Suite struct {
+	Dir         string // path to the package directory, under the root passed to Discover
+	PackageName string // the package's own declared name, e.g. "integration"
+	Name        string // suite name derived from Dir's base, e.g. "Project"
+}
+
+// Discover walks root looking for directories containing Describe(...)
+// blocks but no Test* bootstrap function, skipping any path with a
+// "vendor" path segment so third-party test files are never rewritten.
+func Discover(root string) ([]Suite, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var suites []Suite
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if hasVendorSegment(path) {
+			return filepath.SkipDir
+		}
+
+		hasDescribe, hasBootstrap, packageName, err := inspectDir(path)
+		if err != nil {
+			return err
+		}
+		if hasDescribe && !hasBootstrap {
+			suites = append(suites, Suite{
+				Dir:         path,
+				PackageName: packageName,
+				Name:        suiteName(path),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return suites, nil
+}
+
+func hasVendorSegment(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectDir reports whether dir's *_test.go files contain a Describe(...)
+// call, whether any of them already define a Test* bootstrap function, and
+// the package name they declare.
+func inspectDir(dir string) (hasDescribe, hasBootstrap bool, packageName string, err error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, false, "", err
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, 0)
+		if err != nil {
+			return false, false, "", err
+		}
+		packageName = file.Name.Name
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "Describe" {
+					hasDescribe = true
+				}
+			}
+			if funcDecl, ok := n.(*ast.FuncDecl); ok && funcDecl.Recv == nil && strings.HasPrefix(funcDecl.Name.Name, "Test") {
+				hasBootstrap = true
+			}
+			return true
+		})
+	}
+	return hasDescribe, hasBootstrap, packageName, nil
+}
+
+// suiteName turns a directory basename like "loginlogout" or
+// "service-catalog" into a CamelCase suite name such as "Loginlogout" or
+// "ServiceCatalog".
+func suiteName(dir string) string {
+	base := filepath.Base(dir)
+	parts := strings.FieldsFunc(base, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+var suiteTemplate = template.Must(template.New("suite").Parse(`package {{.PackageName}}
+
+// Code generated by 'make gen-suites'; DO NOT EDIT.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/odo/tests/helper"
+)
+
+func Test{{.Name}}(t *testing.T) {
+	RegisterFailHandler(Fail)
+	// JUnit output is no longer wired up by hand here: run with
+	// --ginkgo.junit-report=reports/{{.ReportSlug}}-junit.xml to get it.
+	RunSpecs(t, "{{.Name}} Suite")
+}
+
+var kubeconfigDir string
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	return nil
+}, func(_ []byte) {
+	dir, err := helper.SandboxKubeconfig(GinkgoParallelProcess())
+	Expect(err).NotTo(HaveOccurred())
+	if dir != "" {
+		kubeconfigDir = dir
+		Expect(os.Setenv("KUBECONFIG", filepath.Join(dir, "kubeconfig"))).To(Succeed())
+	}
+})
+
+var _ = SynchronizedAfterSuite(func() {
+	if kubeconfigDir != "" {
+		helper.DeleteDir(kubeconfigDir)
+	}
+}, func() {})
+`))
+
+// Render emits the canonical suite_test.go contents for s.
+func Render(s Suite) ([]byte, error) {
+	data := struct {
+		PackageName string
+		Name        string
+		ReportSlug  string
+	}{
+		PackageName: s.PackageName,
+		Name:        s.Name,
+		ReportSlug:  strings.ToLower(s.Name),
+	}
+
+	var buf strings.Builder
+	if err := suiteTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
+
+// Write renders s and writes it to <s.Dir>/suite_test.go.
+func Write(s Suite) error {
+	content, err := Render(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.Dir, "suite_test.go"), content, 0644)
+}
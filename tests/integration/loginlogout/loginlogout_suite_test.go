@@ -5,35 +5,37 @@ import (
 	"path/filepath"
 	"testing"
 
-	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	"github.com/openshift/odo/pkg/util"
 	"github.com/openshift/odo/tests/helper"
 )
 
 func TestLoginlogout(t *testing.T) {
 	RegisterFailHandler(Fail)
+	// JUnit output is no longer wired up by hand here: run with
+	// --ginkgo.junit-report=reports/loginlogout-junit.xml to get it.
 	RunSpecs(t, "Loginlogout Suite")
-	// Keep CustomReporters commented till https://github.com/onsi/ginkgo/issues/628 is fixed
-	// RunSpecsWithDefaultAndCustomReporters(t, "Loginlogout Suite", []Reporter{reporter.JunitReport(t, "../../../reports")})
 }
 
-
-var tempdir string
-
-var _ = BeforeSuite(func() {
-	originalKubeconfig := os.Getenv("KUBECONFIG")
-	if len(originalKubeconfig) > 0 {
-		tempdir = helper.CreateNewContext()
-		info, err := os.Stat(originalKubeconfig)
-		Expect(err).NotTo(HaveOccurred())
-
-		err = util.CopyFile(originalKubeconfig, filepath.Join(tempdir, "kubeconfig"), info)
-		Expect(err).NotTo(HaveOccurred())
+var kubeconfigDir string
+
+// This suite mutates current-context, tokens and users, so under `ginkgo
+// -p` every parallel node needs its own KUBECONFIG rather than the single
+// shared tempdir the other suites use - see helper.SandboxKubeconfig.
+var _ = SynchronizedBeforeSuite(func() []byte {
+	return nil
+}, func(_ []byte) {
+	dir, err := helper.SandboxKubeconfig(GinkgoParallelProcess())
+	Expect(err).NotTo(HaveOccurred())
+	if dir != "" {
+		kubeconfigDir = dir
+		Expect(os.Setenv("KUBECONFIG", filepath.Join(dir, "kubeconfig"))).To(Succeed())
 	}
 })
 
-var _ = AfterSuite(func() {
-	helper.DeleteDir(tempdir)
-})
+var _ = SynchronizedAfterSuite(func() {
+	if kubeconfigDir != "" {
+		helper.DeleteDir(kubeconfigDir)
+	}
+}, func() {})
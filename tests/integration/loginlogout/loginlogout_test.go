@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"fmt"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/odo/tests/helper"
+)
+
+var _ = Describe("odo login and logout", func() {
+	authModes := []helper.AuthMode{
+		helper.AuthModeUsernamePassword,
+		helper.AuthModeBearerToken,
+		helper.AuthModeClientCert,
+		helper.AuthModeExecPlugin,
+	}
+
+	for _, mode := range authModes {
+		mode := mode
+
+		Context(fmt.Sprintf("using a %s kubeconfig", mode), func() {
+			It("logs in with the expected AuthInfo and logs out without losing the cluster/context entries", func() {
+				if kubeconfigDir == "" {
+					Skip("no $KUBECONFIG available to derive a variant from")
+				}
+
+				variant, err := helper.SynthesizeKubeconfigVariant(filepath.Join(kubeconfigDir, "kubeconfig"), kubeconfigDir, mode)
+				Expect(err).NotTo(HaveOccurred())
+
+				loginArgs, err := helper.LoginArgsForMode(mode, kubeconfigDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				if !helper.AuthModeHasRealCredentials(mode) {
+					// No credential the test cluster will actually authenticate is
+					// available for this mode, so `odo login` would fail before the
+					// AuthInfo-persistence behavior under test is ever reached. Assert
+					// the variant kubeconfig has the shape odo login/logout expect
+					// instead of driving a live round trip against the cluster.
+					authInfo, err := helper.AuthInfoForContext(variant)
+					Expect(err).NotTo(HaveOccurred())
+					switch mode {
+					case helper.AuthModeUsernamePassword:
+						Expect(authInfo.Username).NotTo(BeEmpty())
+					case helper.AuthModeBearerToken:
+						Expect(authInfo.Token).NotTo(BeEmpty())
+					case helper.AuthModeClientCert:
+						Expect(authInfo.ClientCertificateData).NotTo(BeEmpty())
+					case helper.AuthModeExecPlugin:
+						Expect(authInfo.Exec).NotTo(BeNil())
+					}
+					if mode == helper.AuthModeExecPlugin {
+						Expect(loginArgs).To(BeEmpty())
+					} else {
+						Expect(loginArgs).NotTo(BeEmpty())
+					}
+					return
+				}
+
+				helper.CmdShouldPass("odo", append([]string{"login", "--kubeconfig", variant}, loginArgs...)...)
+
+				loggedIn, err := helper.AuthInfoForContext(variant)
+				Expect(err).NotTo(HaveOccurred())
+				switch mode {
+				case helper.AuthModeUsernamePassword:
+					Expect(loggedIn.Username).NotTo(BeEmpty())
+				case helper.AuthModeBearerToken:
+					Expect(loggedIn.Token).NotTo(BeEmpty())
+				case helper.AuthModeClientCert:
+					Expect(loggedIn.ClientCertificateData).NotTo(BeEmpty())
+				case helper.AuthModeExecPlugin:
+					Expect(loggedIn.Exec).NotTo(BeNil())
+				}
+
+				helper.CmdShouldPass("odo", "logout", "--kubeconfig", variant)
+
+				loggedOut, err := helper.AuthInfoForContext(variant)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loggedOut.Token).To(BeEmpty())
+				Expect(loggedOut.Password).To(BeEmpty())
+
+				hasClusterAndContext, err := helper.KubeconfigHasClusterAndContext(variant)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasClusterAndContext).To(BeTrue())
+			})
+		})
+	}
+})
@@ -5,7 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
-	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/openshift/odo/pkg/util"
@@ -14,9 +14,9 @@ import (
 
 func TestProject(t *testing.T) {
 	RegisterFailHandler(Fail)
+	// JUnit output is no longer wired up by hand here: run with
+	// --ginkgo.junit-report=reports/project-junit.xml to get it.
 	RunSpecs(t, "Project Suite")
-	// Keep CustomReporters commented till https://github.com/onsi/ginkgo/issues/628 is fixed
-	// RunSpecsWithDefaultAndCustomReporters(t, "Project Suite", []Reporter{reporter.JunitReport(t, "../../../reports")})
 }
 
 var tempdir string